@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/charlescqian/go-scrape/scraper"
+)
+
+// The outcome of one entry of a batch, tagged with its format so clients
+// know which of Result's fields to read
+type batchEntryResult struct {
+	URL string `json:"url"`
+	// The entry's format, "text" if it didn't pick one
+	Format string          `json:"format"`
+	Result *scrapeResponse `json:"result,omitempty"`
+	Error  string          `json:"error,omitempty"`
+}
+
+// Reads and checks the body of a batch request of at most maxURLs pages,
+// writing the error response and returning false if it's no good
+func decodeBatch(w http.ResponseWriter, r *http.Request, maxURLs int) (batchRequest, bool) {
+	var req batchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, codeInvalidJSON, "Invalid JSON")
+		return req, false
+	}
+	if len(req.URLs) > 0 && len(req.Entries) > 0 {
+		writeError(w, http.StatusBadRequest, codeInvalidRequest, "Only one of urls and entries may be set")
+		return req, false
+	}
+	if len(req.URLs) > maxURLs || len(req.Entries) > maxURLs {
+		writeError(w, http.StatusBadRequest, codeTooManyURLs, fmt.Sprintf("Too many URLs (max %d)", maxURLs))
+		return req, false
+	}
+
+	for _, u := range req.URLs {
+		if err := scraper.ValidateURL(u); err != nil {
+			writeError(w, http.StatusBadRequest, codeInvalidURL, err.Error())
+			return req, false
+		}
+	}
+	for i, e := range req.Entries {
+		if err := scraper.ValidateURL(e.URL); err != nil {
+			writeError(w, http.StatusBadRequest, codeInvalidURL, fmt.Sprintf("entries[%d]: %v", i, err))
+			return req, false
+		}
+		if err := validateRequest(e); err != nil {
+			writeError(w, http.StatusBadRequest, codeInvalidRequest, fmt.Sprintf("entries[%d]: %v", i, err))
+			return req, false
+		}
+	}
+	return req, true
+}
+
+// Scrapes each entry as /scrape would, batchConcurrency at a time, calling
+// done with the index and result of each from the worker that scraped it.
+// Entries not yet started when ctx is done fail with ctx.Err().
+func scrapeEntries(ctx context.Context, entries []scrapeRequest, done func(int, batchEntryResult)) {
+	result := func(e scrapeRequest, resp *scrapeResponse, err error) batchEntryResult {
+		res := batchEntryResult{URL: e.URL, Format: e.Format, Result: resp}
+		if res.Format == "" {
+			res.Format = "text"
+		}
+		if err != nil {
+			res.Result = nil
+			res.Error = err.Error()
+		}
+		return res
+	}
+
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+	for range min(batchConcurrency, len(entries)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				start := time.Now()
+				resp, err := extract(ctx, entries[i])
+				if err != nil && ctx.Err() != nil {
+					err = ctx.Err()
+				}
+				observeScrape(start, err)
+				done(i, result(entries[i], &resp, err))
+			}
+		}()
+	}
+
+	next := 0
+feed:
+	for ; next < len(entries); next++ {
+		select {
+		case jobs <- next:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	for i := next; i < len(entries); i++ {
+		done(i, result(entries[i], nil, ctx.Err()))
+	}
+}
@@ -0,0 +1,228 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+
+	"github.com/charlescqian/go-scrape/scraper"
+)
+
+// Body of every error response, e.g.
+// {"error":{"code":"FETCH_FAILED","message":"...","status":502}}
+type errorResponse struct {
+	Error apiError `json:"error"`
+}
+
+type apiError struct {
+	// Stable, machine-readable reason for the failure, one of the codes below
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	// The HTTP status of the response, repeated for clients that only
+	// keep the body
+	Status int `json:"status"`
+	// Status the target responded with, when it responded with an error
+	UpstreamStatus int `json:"upstream_status,omitempty"`
+}
+
+// Error codes for requests the API turned down
+const (
+	codeInvalidJSON      = "INVALID_JSON"
+	codeInvalidURL       = "INVALID_URL"
+	codeInvalidRequest   = "INVALID_REQUEST"
+	codeTooManyURLs      = "TOO_MANY_URLS"
+	codeMethodNotAllowed = "METHOD_NOT_ALLOWED"
+	codeServerBusy       = "SERVER_BUSY"
+	codeUnauthorized     = "UNAUTHORIZED"
+)
+
+// Error codes for scrapes that failed
+const (
+	codeFetchFailed            = "FETCH_FAILED"
+	codeDNSFailed              = "DNS_FAILED"
+	codeConnectionFailed       = "CONNECTION_FAILED"
+	codeTLSFailed              = "TLS_FAILED"
+	codeNetworkError           = "NETWORK_ERROR"
+	codeTimeout                = "TIMEOUT"
+	codeCanceled               = "CANCELED"
+	codeDisallowedByRobots     = "DISALLOWED_BY_ROBOTS"
+	codeUnsupportedContentType = "UNSUPPORTED_CONTENT_TYPE"
+	codeNotAFeed               = "NOT_A_FEED"
+	codeBodyTooLarge           = "BODY_TOO_LARGE"
+	codeIncompleteResponse     = "INCOMPLETE_RESPONSE"
+	codeTooManyRedirects       = "TOO_MANY_REDIRECTS"
+	codeRedirectLoop           = "REDIRECT_LOOP"
+	codeUnsafeRedirect         = "UNSAFE_REDIRECT"
+	codeCircuitOpen            = "CIRCUIT_OPEN"
+	codeRedirected             = "REDIRECTED"
+	codeParseFailed            = "PARSE_FAILED"
+	codeInternal               = "INTERNAL_ERROR"
+)
+
+// Seconds clients are asked to wait before scraping a host again once the
+// circuit breaker has cut it off
+const circuitRetryAfterSeconds = "30"
+
+// Picks the response status for a failed scrape. A missing page is passed
+// through, other upstream failures and unreachable hosts are a bad gateway
+// and timeouts are a gateway timeout. Pages the scraper won't take, because
+// of robots.txt or their content type, are forbidden and unprocessable, and
+// a host cut off by the circuit breaker is unavailable for now.
+func scrapeErrorStatus(err error) int {
+	var fetchErr *scraper.FetchError
+	if errors.As(err, &fetchErr) {
+		switch fetchErr.StatusCode {
+		case http.StatusNotFound, http.StatusGone:
+			return fetchErr.StatusCode
+		}
+		return http.StatusBadGateway
+	}
+
+	var networkErr *scraper.NetworkError
+	if errors.As(err, &networkErr) {
+		if networkErr.Kind == scraper.NetworkTimeout {
+			return http.StatusGatewayTimeout
+		}
+		return http.StatusBadGateway
+	}
+
+	var redirectErr *scraper.RedirectError
+	switch {
+	case errors.Is(err, scraper.ErrIncomplete), errors.Is(err, scraper.ErrBodyTooLarge),
+		errors.Is(err, scraper.ErrTooManyRedirects), errors.Is(err, scraper.ErrRedirectLoop),
+		errors.Is(err, scraper.ErrUnsafeRedirect), errors.As(err, &redirectErr):
+		return http.StatusBadGateway
+	case errors.Is(err, scraper.ErrDisallowedByRobots):
+		return http.StatusForbidden
+	case errors.Is(err, scraper.ErrUnsupportedContentType), errors.Is(err, scraper.ErrNotFeed):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, scraper.ErrCircuitOpen):
+		return http.StatusServiceUnavailable
+	}
+
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return http.StatusGatewayTimeout
+	}
+	return http.StatusInternalServerError
+}
+
+// Buckets a scrape failure for metrics
+func errorCategory(err error) string {
+	var fetchErr *scraper.FetchError
+	var parseErr *scraper.ParseError
+	var redirectErr *scraper.RedirectError
+	var networkErr *scraper.NetworkError
+	var netErr net.Error
+	switch {
+	case errors.As(err, &fetchErr):
+		return "upstream_status"
+	case errors.As(err, &networkErr):
+		return string(networkErr.Kind)
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return "timeout"
+	case errors.Is(err, scraper.ErrDisallowedByRobots):
+		return "robots"
+	case errors.Is(err, scraper.ErrCircuitOpen):
+		return "circuit_open"
+	case errors.Is(err, scraper.ErrUnsupportedContentType), errors.Is(err, scraper.ErrNotFeed):
+		return "content_type"
+	case errors.Is(err, scraper.ErrBodyTooLarge):
+		return "body_too_large"
+	case errors.Is(err, scraper.ErrIncomplete):
+		return "incomplete"
+	case errors.Is(err, scraper.ErrTooManyRedirects), errors.Is(err, scraper.ErrRedirectLoop),
+		errors.Is(err, scraper.ErrUnsafeRedirect), errors.As(err, &redirectErr):
+		return "redirect"
+	case errors.As(err, &parseErr):
+		return "parse"
+	case errors.As(err, &netErr):
+		return "network"
+	}
+	return "other"
+}
+
+// Picks the error code for a failed scrape
+func scrapeErrorCode(err error) string {
+	var fetchErr *scraper.FetchError
+	var parseErr *scraper.ParseError
+	var redirectErr *scraper.RedirectError
+	var networkErr *scraper.NetworkError
+	var netErr net.Error
+	switch {
+	case errors.As(err, &fetchErr):
+		return codeFetchFailed
+	case errors.As(err, &networkErr):
+		switch networkErr.Kind {
+		case scraper.NetworkDNS:
+			return codeDNSFailed
+		case scraper.NetworkTLS:
+			return codeTLSFailed
+		case scraper.NetworkTimeout:
+			return codeTimeout
+		}
+		return codeConnectionFailed
+	case errors.Is(err, context.Canceled):
+		return codeCanceled
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return codeTimeout
+	case errors.Is(err, scraper.ErrDisallowedByRobots):
+		return codeDisallowedByRobots
+	case errors.Is(err, scraper.ErrCircuitOpen):
+		return codeCircuitOpen
+	case errors.Is(err, scraper.ErrUnsupportedContentType):
+		return codeUnsupportedContentType
+	case errors.Is(err, scraper.ErrNotFeed):
+		return codeNotAFeed
+	case errors.Is(err, scraper.ErrBodyTooLarge):
+		return codeBodyTooLarge
+	case errors.Is(err, scraper.ErrIncomplete):
+		return codeIncompleteResponse
+	case errors.Is(err, scraper.ErrTooManyRedirects):
+		return codeTooManyRedirects
+	case errors.Is(err, scraper.ErrRedirectLoop):
+		return codeRedirectLoop
+	case errors.Is(err, scraper.ErrUnsafeRedirect):
+		return codeUnsafeRedirect
+	case errors.As(err, &redirectErr):
+		return codeRedirected
+	case errors.As(err, &parseErr):
+		return codeParseFailed
+	case errors.As(err, &netErr):
+		return codeNetworkError
+	}
+	return codeInternal
+}
+
+// Writes a JSON error body with the given status and code
+func writeError(w http.ResponseWriter, status int, code, msg string) {
+	writeErrorResponse(w, apiError{Code: code, Message: msg, Status: status})
+}
+
+// Writes a JSON error body for a failed scrape, including the target's
+// status code when it responded with an error
+func writeScrapeError(w http.ResponseWriter, err error) {
+	e := apiError{
+		Code:    scrapeErrorCode(err),
+		Message: "Scraping failed: " + err.Error(),
+		Status:  scrapeErrorStatus(err),
+	}
+	var fetchErr *scraper.FetchError
+	if errors.As(err, &fetchErr) {
+		e.UpstreamStatus = fetchErr.StatusCode
+	}
+	if errors.Is(err, scraper.ErrCircuitOpen) {
+		w.Header().Set("Retry-After", circuitRetryAfterSeconds)
+	}
+	writeErrorResponse(w, e)
+}
+
+func writeErrorResponse(w http.ResponseWriter, e apiError) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(e.Status)
+	json.NewEncoder(w).Encode(errorResponse{Error: e})
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charlescqian/go-scrape/scraper"
+)
+
+// Output formats accepted in scrapeRequest.Format
+var formats = []string{"text", "paragraphs", "markdown", "links", "metadata", "feed"}
+
+// Reports whether format produces text, which hashing and chunking need
+func extractsText(format string) bool {
+	switch format {
+	case "links", "metadata", "feed":
+		return false
+	}
+	return true
+}
+
+// Checks the extraction options of req, returning a message for the client
+// if they don't make sense
+func validateRequest(req scrapeRequest) error {
+	format := req.Format
+	if format == "" {
+		format = "text"
+	}
+
+	known := false
+	for _, f := range formats {
+		known = known || f == format
+	}
+	if !known {
+		return fmt.Errorf("unknown format %q, expected one of: %s", req.Format, strings.Join(formats, ", "))
+	}
+
+	if req.Selector != "" && format != "text" {
+		return fmt.Errorf("selector is only supported with the text format")
+	}
+	if req.Stats && (format != "text" || req.Selector != "") {
+		return fmt.Errorf("stats is only supported with the text format and no selector")
+	}
+	if req.Hash && !extractsText(format) {
+		return fmt.Errorf("hash is only supported with formats that extract text")
+	}
+	if req.Timeout < 0 || req.TimeoutMs < 0 {
+		return fmt.Errorf("timeout and timeout_ms must not be negative")
+	}
+	if req.Timeout > 0 && req.TimeoutMs > 0 {
+		return fmt.Errorf("only one of timeout and timeout_ms may be set")
+	}
+	if req.ChunkSize < 0 || req.ChunkOverlap < 0 {
+		return fmt.Errorf("chunk_size and chunk_overlap must not be negative")
+	}
+	if req.ChunkSize > 0 && !extractsText(format) {
+		return fmt.Errorf("chunk_size is only supported with formats that extract text")
+	}
+	if req.ChunkOverlap > 0 && req.ChunkOverlap >= req.ChunkSize {
+		return fmt.Errorf("chunk_overlap must be smaller than chunk_size")
+	}
+	return nil
+}
+
+// Returns how long req's scrape may take, capped at maxRequestTimeout, or 0
+// if it didn't ask for a limit
+func requestTimeout(req scrapeRequest) time.Duration {
+	var d time.Duration
+	switch {
+	case req.TimeoutMs > 0:
+		d = time.Duration(req.TimeoutMs) * time.Millisecond
+	case req.Timeout > 0:
+		d = time.Duration(req.Timeout) * time.Second
+	default:
+		return 0
+	}
+	return min(d, maxRequestTimeout)
+}
+
+// Runs the extraction req asks for. req must have passed validateRequest.
+func extract(ctx context.Context, req scrapeRequest) (scrapeResponse, error) {
+	timeout := requestTimeout(req)
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	var resp scrapeResponse
+	var err error
+	var text string
+
+	switch req.Format {
+	case "", "text":
+		if req.Selector != "" {
+			resp.Content, err = scr.ExtractTextBySelector(ctx, req.URL, req.Selector)
+			resp.Truncated = errors.Is(err, scraper.ErrTruncated)
+			resp.Incomplete = errors.Is(err, scraper.ErrIncomplete)
+			if resp.Truncated || resp.Incomplete {
+				err = nil
+			}
+		} else {
+			var res *scraper.ExtractResult
+			if req.Stats {
+				var stats scraper.ExtractionStats
+				res, stats, err = scr.ExtractWithStats(ctx, req.URL)
+				resp.Stats = &scrapeStats{
+					FetchMs:         milliseconds(stats.FetchDuration),
+					ParseMs:         milliseconds(stats.ParseDuration),
+					BytesDownloaded: stats.BytesDownloaded,
+				}
+			} else {
+				res, err = scr.ExtractTextWithWarnings(ctx, req.URL)
+			}
+			if err == nil {
+				resp.Content = res.Text
+				resp.Warnings = res.Warnings
+				for _, w := range res.Warnings {
+					resp.Truncated = resp.Truncated || w.Code == scraper.WarningTruncated
+					resp.Incomplete = resp.Incomplete || w.Code == scraper.WarningIncomplete
+				}
+			}
+		}
+		text = resp.Content
+	case "paragraphs":
+		resp.Paragraphs, err = scr.ExtractParagraphs(ctx, req.URL)
+		text = strings.Join(resp.Paragraphs, "\n\n")
+	case "markdown":
+		resp.Content, err = scr.ExtractMarkdown(ctx, req.URL)
+		text = resp.Content
+	case "links":
+		resp.Links, err = scr.ExtractLinks(ctx, req.URL)
+	case "metadata":
+		resp.Metadata, err = scr.ExtractMetadata(ctx, req.URL)
+	case "feed":
+		resp.Items, err = scr.DetectAndParseFeed(ctx, req.URL)
+	}
+	if err != nil {
+		if timeout > 0 && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			err = fmt.Errorf("did not finish within %s: %w", timeout, err)
+		}
+		return scrapeResponse{}, err
+	}
+
+	stats := scraper.Stats(text)
+	resp.WordCount = stats.WordCount
+	resp.EstimatedReadingMinutes = stats.EstimatedReadingMinutes
+	if req.Hash {
+		resp.ContentHash = scraper.HashText(text)
+	}
+	if req.ChunkSize > 0 {
+		resp.Chunks = scraper.ChunkTextWithOverlap(text, req.ChunkSize, req.ChunkOverlap)
+	}
+	return resp, nil
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"compress/gzip"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Responses smaller than this are sent as they are, as compressing them
+// saves next to nothing
+const minGzipSize = 1024
+
+// Returns a middleware that gzips the responses of the handlers it wraps
+// for clients that accept it, unless they're tiny
+func gzipResponses(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept-Encoding")
+		if !acceptsGzip(r.Header.Get("Accept-Encoding")) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		gw := &gzipResponseWriter{ResponseWriter: w, status: http.StatusOK}
+		defer gw.finish()
+		next.ServeHTTP(gw, r)
+	})
+}
+
+// Reports whether an Accept-Encoding header allows gzip
+func acceptsGzip(accept string) bool {
+	for _, part := range strings.Split(accept, ",") {
+		coding, params, _ := strings.Cut(part, ";")
+		if !strings.EqualFold(strings.TrimSpace(coding), "gzip") {
+			continue
+		}
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			q, _ = strconv.ParseFloat(v, 64)
+		}
+		return q > 0
+	}
+	return false
+}
+
+// Holds back the start of the body until it's clear whether it's big enough
+// to compress, then either gzips it or passes it through as it is
+type gzipResponseWriter struct {
+	http.ResponseWriter
+	status int
+	buf    []byte
+	gz     *gzip.Writer
+	// Set once the status has been sent and the body is going straight
+	// through uncompressed
+	passthrough bool
+}
+
+func (w *gzipResponseWriter) WriteHeader(status int) {
+	w.status = status
+}
+
+func (w *gzipResponseWriter) Write(p []byte) (int, error) {
+	switch {
+	case w.gz != nil:
+		return w.gz.Write(p)
+	case w.passthrough:
+		return w.ResponseWriter.Write(p)
+	}
+
+	w.buf = append(w.buf, p...)
+	if len(w.buf) >= minGzipSize {
+		if err := w.start(); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// Sends the status and what's been held back, compressed if the handler
+// didn't pick its own encoding
+func (w *gzipResponseWriter) start() error {
+	h := w.Header()
+	if h.Get("Content-Encoding") != "" {
+		w.passthrough = true
+	} else {
+		h.Set("Content-Encoding", "gzip")
+		h.Del("Content-Length")
+		w.gz = gzip.NewWriter(w.ResponseWriter)
+	}
+	w.ResponseWriter.WriteHeader(w.status)
+
+	buf := w.buf
+	w.buf = nil
+	_, err := w.Write(buf)
+	return err
+}
+
+// Sends a response too small to compress, or ends the compressed stream
+func (w *gzipResponseWriter) finish() {
+	switch {
+	case w.gz != nil:
+		w.gz.Close()
+	case !w.passthrough:
+		w.passthrough = true
+		w.ResponseWriter.WriteHeader(w.status)
+		w.ResponseWriter.Write(w.buf)
+	}
+}
+
+// Sends what has been written so far, compressing it whatever its size, as
+// a streaming handler won't wait for more
+func (w *gzipResponseWriter) Flush() {
+	if w.gz == nil && !w.passthrough {
+		w.start()
+	}
+	if w.gz != nil {
+		w.gz.Flush()
+	}
+	http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Lets http.ResponseController reach the real writer, e.g. for deadlines
+func (w *gzipResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
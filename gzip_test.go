@@ -0,0 +1,114 @@
+package main
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAcceptsGzip(t *testing.T) {
+	tests := []struct {
+		accept string
+		want   bool
+	}{
+		{"", false},
+		{"gzip", true},
+		{"deflate, gzip;q=0.5", true},
+		{"GZIP", true},
+		{"gzip;q=0", false},
+		{"br, deflate", false},
+	}
+	for _, tt := range tests {
+		if got := acceptsGzip(tt.accept); got != tt.want {
+			t.Errorf("acceptsGzip(%q) = %v, want %v", tt.accept, got, tt.want)
+		}
+	}
+}
+
+func TestGzipResponses(t *testing.T) {
+	big := strings.Repeat("word ", minGzipSize)
+	tests := []struct {
+		name     string
+		accept   string
+		body     string
+		encoding string
+		gzipped  bool
+	}{
+		{"not accepted", "", big, "", false},
+		{"tiny", "gzip", "short", "", false},
+		{"big", "gzip", big, "", true},
+		{"handler's own encoding", "gzip", big, "br", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := gzipResponses(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.encoding != "" {
+					w.Header().Set("Content-Encoding", tt.encoding)
+				}
+				w.WriteHeader(http.StatusTeapot)
+				io.WriteString(w, tt.body)
+			}))
+			req := httptest.NewRequest(http.MethodGet, "/scrape", nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept-Encoding", tt.accept)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if rec.Code != http.StatusTeapot {
+				t.Errorf("status = %d, want the handler's %d", rec.Code, http.StatusTeapot)
+			}
+			if rec.Header().Get("Vary") != "Accept-Encoding" {
+				t.Errorf("Vary = %q, want Accept-Encoding", rec.Header().Get("Vary"))
+			}
+			body := rec.Body.String()
+			if gzipped := rec.Header().Get("Content-Encoding") == "gzip"; gzipped != tt.gzipped {
+				t.Fatalf("gzipped = %v, want %v", gzipped, tt.gzipped)
+			}
+			if tt.gzipped {
+				zr, err := gzip.NewReader(rec.Body)
+				if err != nil {
+					t.Fatal(err)
+				}
+				b, err := io.ReadAll(zr)
+				if err != nil {
+					t.Fatal(err)
+				}
+				body = string(b)
+			}
+			if body != tt.body {
+				t.Errorf("body = %.20q..., want %.20q...", body, tt.body)
+			}
+		})
+	}
+}
+
+// A streaming handler's flushes go out compressed straight away, however
+// little has been written
+func TestGzipResponsesFlush(t *testing.T) {
+	h := gzipResponses(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "line\n")
+		http.NewResponseController(w).Flush()
+	}))
+	req := httptest.NewRequest(http.MethodPost, "/scrape/stream", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if !rec.Flushed {
+		t.Error("response wasn't flushed")
+	}
+	if rec.Header().Get("Content-Encoding") != "gzip" {
+		t.Fatalf("Content-Encoding = %q, want gzip", rec.Header().Get("Content-Encoding"))
+	}
+	zr, err := gzip.NewReader(rec.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b, _ := io.ReadAll(zr); string(b) != "line\n" {
+		t.Errorf("body = %q, want %q", b, "line\n")
+	}
+}
@@ -1,48 +1,319 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
-	"fmt"
+	"flag"
+	"log"
+	"log/slog"
 	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
 
 	"github.com/charlescqian/go-scrape/scraper"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
 type scrapeRequest struct {
 	URL string `json:"url"`
+	// One of formats, "text" by default
+	Format string `json:"format"`
+	// CSS selector limiting text extraction to matching elements
+	Selector string `json:"selector"`
+	// Seconds the scrape may take, 0 for no extra limit
+	Timeout int `json:"timeout"`
+	// Same as Timeout but in milliseconds, for finer limits. Both are
+	// capped at maxRequestTimeout.
+	TimeoutMs int `json:"timeout_ms"`
+	// Include a hash of the extracted text, for change detection
+	Hash bool `json:"hash"`
+	// Also split the text into chunks of at most this many characters,
+	// each starting with up to ChunkOverlap characters of the one before
+	ChunkSize    int `json:"chunk_size"`
+	ChunkOverlap int `json:"chunk_overlap"`
+	// Report how long the fetch and the parsing took
+	Stats bool `json:"stats"`
 }
 
 type scrapeResponse struct {
+	Content                 string                `json:"content,omitempty"`
+	Paragraphs              []string              `json:"paragraphs,omitempty"`
+	Chunks                  []string              `json:"chunks,omitempty"`
+	Links                   []string              `json:"links,omitempty"`
+	Metadata                *scraper.PageMetadata `json:"metadata,omitempty"`
+	Items                   []scraper.FeedItem    `json:"items,omitempty"`
+	WordCount               int                   `json:"word_count"`
+	EstimatedReadingMinutes float64               `json:"estimated_reading_minutes"`
+	ContentHash             string                `json:"content_hash,omitempty"`
+	// The page was too large or deeply nested to extract all of its text
+	Truncated bool `json:"truncated,omitempty"`
+	// The download broke off, so the content is only what was received
+	Incomplete bool `json:"incomplete,omitempty"`
+	// What was off about the page, though its content could be extracted
+	Warnings []scraper.Warning `json:"warnings,omitempty"`
+	Stats    *scrapeStats      `json:"stats,omitempty"`
+}
+
+// Timings of a scrape, in milliseconds
+type scrapeStats struct {
+	FetchMs         float64 `json:"fetch_ms"`
+	ParseMs         float64 `json:"parse_ms"`
+	BytesDownloaded int64   `json:"bytes_downloaded"`
+}
+
+// Shared by all handlers
+var scr, _ = scraper.NewScraper()
+
+type batchRequest struct {
+	URLs []string `json:"urls"`
+	// Instead of URLs, pages each with their own format and options, as
+	// they would be sent to /scrape
+	Entries []scrapeRequest `json:"entries"`
+}
+
+type batchResult struct {
+	URL     string `json:"url"`
 	Content string `json:"content"`
+	Error   string `json:"error,omitempty"`
 }
 
-// Handler for POST /scrape
+const (
+	maxBatchURLs     = 100
+	maxStreamURLs    = 10000
+	batchConcurrency = 8
+	shutdownTimeout  = 30 * time.Second
+
+	// Longest a client may ask a single scrape to run for
+	maxRequestTimeout = 2 * time.Minute
+)
+
+// Handler for GET and POST /scrape
 func scrapeHandler(w http.ResponseWriter, r *http.Request) {
-	// Check that it's a POST request
-	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	var req scrapeRequest
+
+	switch r.Method {
+	case http.MethodGet:
+		// Read the target from the query string, e.g. /scrape?url=...
+		q := r.URL.Query()
+		req.URL = q.Get("url")
+		req.Format = q.Get("format")
+		req.Selector = q.Get("selector")
+		req.Hash = q.Get("hash") == "true"
+		req.Stats = q.Get("stats") == "true"
+		for name, field := range map[string]*int{
+			"timeout":       &req.Timeout,
+			"timeout_ms":    &req.TimeoutMs,
+			"chunk_size":    &req.ChunkSize,
+			"chunk_overlap": &req.ChunkOverlap,
+		} {
+			if v := q.Get(name); v != "" {
+				n, err := strconv.Atoi(v)
+				if err != nil {
+					writeError(w, http.StatusBadRequest, codeInvalidRequest, "Invalid "+name+" parameter")
+					return
+				}
+				*field = n
+			}
+		}
+	case http.MethodPost:
+		// Decode the JSON body into a Go struct
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, codeInvalidJSON, "Invalid JSON")
+			return
+		}
+	default:
+		writeError(w, http.StatusMethodNotAllowed, codeMethodNotAllowed, "Method not allowed")
 		return
 	}
+	setTargetURL(r, req.URL)
 
-	// Decode the JSON body into a Go struct
-	var req scrapeRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+	if err := scraper.ValidateURL(req.URL); err != nil {
+		writeError(w, http.StatusBadRequest, codeInvalidURL, err.Error())
+		return
+	}
+	if err := validateRequest(req); err != nil {
+		writeError(w, http.StatusBadRequest, codeInvalidRequest, err.Error())
 		return
 	}
 
-	content, err := scraper.ExtractTextFromURL((req.URL))
+	start := time.Now()
+	resp, err := extract(r.Context(), req)
+	observeScrape(start, err)
 	if err != nil {
-		http.Error(w, "Scraping failed: "+err.Error(), http.StatusInternalServerError)
+		writeScrapeError(w, err)
+		return
+	}
+
+	writeScrapeResponse(w, r, resp, req.Format)
+}
+
+// Handler for POST /scrape/batch
+func batchHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, codeMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	req, ok := decodeBatch(w, r, maxBatchURLs)
+	if !ok {
+		return
+	}
+
+	if len(req.Entries) > 0 {
+		resp := make([]batchEntryResult, len(req.Entries))
+		scrapeEntries(r.Context(), req.Entries, func(i int, res batchEntryResult) {
+			resp[i] = res
+		})
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+		return
+	}
+
+	results := scr.ScrapeBatch(r.Context(), req.URLs, batchConcurrency)
+
+	resp := make([]batchResult, len(results))
+	for i, res := range results {
+		observeBatchResult(res)
+		resp[i] = newBatchResult(res)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// Handler for POST /scrape/stream, which takes the same body as
+// /scrape/batch but writes each page's result as a line of JSON as soon as
+// it's done
+func streamHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, codeMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	req, ok := decodeBatch(w, r, maxStreamURLs)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	enc := json.NewEncoder(w)
+	rc := http.NewResponseController(w)
+
+	if len(req.Entries) > 0 {
+		var mu sync.Mutex
+		scrapeEntries(r.Context(), req.Entries, func(_ int, res batchEntryResult) {
+			mu.Lock()
+			defer mu.Unlock()
+			if err := enc.Encode(res); err == nil {
+				rc.Flush()
+			}
+		})
+		return
+	}
+
+	for res := range scr.ScrapeBatchStream(r.Context(), req.URLs, batchConcurrency) {
+		observeBatchResult(res)
+		if err := enc.Encode(newBatchResult(res)); err != nil {
+			// The client has gone, which also cancels the batch
+			continue
+		}
+		rc.Flush()
+	}
+}
+
+func newBatchResult(res scraper.BatchResult) batchResult {
+	b := batchResult{URL: res.URL, Content: res.Content}
+	if res.Err != nil {
+		b.Error = res.Err.Error()
+	}
+	return b
+}
+
+// Handler for GET /healthz
+func healthHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, codeMethodNotAllowed, "Method not allowed")
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(scrapeResponse{Content: content})
+	json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
+}
+
+// Returns the environment variable key, or fallback if it's unset
+func envOr(key, fallback string) string {
+	if v, ok := os.LookupEnv(key); ok {
+		return v
+	}
+	return fallback
+}
+
+// Returns the environment variable key as an int, or fallback if it's unset
+// or not a number
+func envIntOr(key string, fallback int) int {
+	if n, err := strconv.Atoi(os.Getenv(key)); err == nil {
+		return n
+	}
+	return fallback
 }
 
 func main() {
-	http.HandleFunc("/scrape", scrapeHandler)
-	fmt.Println("Server running on http://localhost:8080")
-	http.ListenAndServe(":8080", nil)
+	addr := flag.String("addr", envOr("ADDR", ":8080"), "address to listen on (env ADDR)")
+	maxConcurrent := flag.Int("max-concurrent", envIntOr("MAX_CONCURRENT", 64), "maximum scrape requests handled at once (env MAX_CONCURRENT)")
+	corsOrigins := flag.String("cors-origins", envOr("CORS_ORIGINS", ""), "comma-separated origins allowed to call the API from a browser, or * for any (env CORS_ORIGINS)")
+	apiKey := flag.String("api-key", envOr("API_KEY", ""), "key clients must send to use the scrape endpoints, none required if empty (env API_KEY)")
+	flag.Parse()
+	if *maxConcurrent < 1 {
+		log.Fatal("max-concurrent must be at least 1")
+	}
+
+	// Checked first, so requests without the key don't take a slot
+	auth := requireAPIKey(*apiKey)
+	limit := limitConcurrency(*maxConcurrent)
+
+	mux := http.NewServeMux()
+	mux.Handle("/scrape", auth(limit(gzipResponses(http.HandlerFunc(scrapeHandler)))))
+	mux.Handle("/scrape/batch", auth(limit(gzipResponses(http.HandlerFunc(batchHandler)))))
+	mux.Handle("/scrape/stream", auth(limit(gzipResponses(http.HandlerFunc(streamHandler)))))
+	mux.HandleFunc("/healthz", healthHandler)
+	mux.Handle("/metrics", promhttp.Handler())
+
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	slog.SetDefault(logger)
+
+	var origins []string
+	for _, o := range strings.Split(*corsOrigins, ",") {
+		if o = strings.TrimSpace(o); o != "" {
+			origins = append(origins, o)
+		}
+	}
+
+	srv := &http.Server{Addr: *addr, Handler: logRequests(logger, allowCORS(origins)(mux))}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	go func() {
+		slog.Info("server running", "addr", *addr)
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatal(err)
+		}
+	}()
+
+	<-ctx.Done()
+	stop()
+
+	// Let in-flight scrapes finish before exiting
+	slog.Info("shutting down")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Fatal(err)
+	}
+	scr.Close()
 }
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"time"
+
+	"github.com/charlescqian/go-scrape/scraper"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	scrapesTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "goscrape_scrapes_total",
+		Help: "Number of URLs scraped, successfully or not.",
+	})
+	scrapeFailures = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "goscrape_scrape_failures_total",
+		Help: "Number of failed scrapes by error category.",
+	}, []string{"category"})
+	scrapeDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "goscrape_scrape_duration_seconds",
+		Help:    "Time taken to fetch and parse a page.",
+		Buckets: []float64{.1, .25, .5, 1, 2.5, 5, 10, 30},
+	})
+)
+
+// Records the outcome of scraping one URL
+func countScrape(err error) {
+	scrapesTotal.Inc()
+	if err != nil {
+		scrapeFailures.WithLabelValues(errorCategory(err)).Inc()
+	}
+}
+
+// Records the outcome and latency of scraping one URL
+func observeScrape(start time.Time, err error) {
+	countScrape(err)
+	scrapeDuration.Observe(time.Since(start).Seconds())
+}
+
+// Records the outcome of one URL in a batch and, if it was started, its
+// latency
+func observeBatchResult(res scraper.BatchResult) {
+	countScrape(res.Err)
+	if res.Duration > 0 {
+		scrapeDuration.Observe(res.Duration.Seconds())
+	}
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Seconds clients are asked to wait when the server is saturated
+const retryAfterSeconds = "1"
+
+// Returns a middleware letting at most n requests into the handlers it wraps
+// at once, counted across all of them. Requests over the limit are turned
+// away with 503 rather than queued.
+func limitConcurrency(n int) func(http.Handler) http.Handler {
+	sem := make(chan struct{}, n)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+				next.ServeHTTP(w, r)
+			default:
+				w.Header().Set("Retry-After", retryAfterSeconds)
+				writeError(w, http.StatusServiceUnavailable, codeServerBusy, "Server busy")
+			}
+		})
+	}
+}
+
+// Returns a middleware that lets browser pages from origins call the
+// handlers it wraps, answering CORS preflight requests itself. "*" allows
+// any origin. With no origins, no CORS headers are sent and browsers keep
+// blocking cross-origin calls.
+func allowCORS(origins []string) func(http.Handler) http.Handler {
+	allowed := make(map[string]bool, len(origins))
+	for _, o := range origins {
+		allowed[o] = true
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			if origin == "" || len(allowed) == 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Add("Vary", "Origin")
+			if !allowed["*"] && !allowed[origin] {
+				next.ServeHTTP(w, r)
+				return
+			}
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+
+			if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+				w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+				if h := r.Header.Get("Access-Control-Request-Headers"); h != "" {
+					w.Header().Set("Access-Control-Allow-Headers", h)
+				}
+				w.Header().Set("Access-Control-Max-Age", "600")
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// Returns a middleware that turns away requests to the handlers it wraps
+// with 401 unless they send key, as "Authorization: Bearer <key>" or
+// "X-API-Key: <key>". Either one matching is enough, so an unrelated bearer
+// token, e.g. added by a proxy, doesn't hide a good X-API-Key. An empty key
+// lets every request through.
+func requireAPIKey(key string) func(http.Handler) http.Handler {
+	// Constant time, so the key can't be guessed from response times
+	matches := func(got string) bool {
+		return subtle.ConstantTimeCompare([]byte(got), []byte(key)) == 1
+	}
+
+	return func(next http.Handler) http.Handler {
+		if key == "" {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ok := matches(r.Header.Get("X-API-Key"))
+			// The scheme is case-insensitive
+			if scheme, token, found := strings.Cut(r.Header.Get("Authorization"), " "); found && strings.EqualFold(scheme, "Bearer") {
+				// Both are always compared, so timing doesn't tell which matched
+				ok = matches(strings.TrimSpace(token)) || ok
+			}
+			if !ok {
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				writeError(w, http.StatusUnauthorized, codeUnauthorized, "Missing or invalid API key")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+type requestInfoKey struct{}
+
+// Details about a request gathered while it's handled, for the access log
+type requestInfo struct {
+	targetURL string
+}
+
+// Records the URL a request asked to scrape, so it shows up in the log
+func setTargetURL(r *http.Request, u string) {
+	if info, ok := r.Context().Value(requestInfoKey{}).(*requestInfo); ok {
+		info.targetURL = u
+	}
+}
+
+// Captures the status code written by a handler
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (rec *statusRecorder) WriteHeader(status int) {
+	rec.status = status
+	rec.ResponseWriter.WriteHeader(status)
+}
+
+// Lets http.ResponseController reach the real writer, e.g. to flush
+func (rec *statusRecorder) Unwrap() http.ResponseWriter {
+	return rec.ResponseWriter
+}
+
+// Logs every request as a JSON line and tags it with a request ID, which
+// is also sent back in the X-Request-ID header
+func logRequests(logger *slog.Logger, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		id := newRequestID()
+		w.Header().Set("X-Request-ID", id)
+
+		info := &requestInfo{}
+		r = r.WithContext(context.WithValue(r.Context(), requestInfoKey{}, info))
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+		next.ServeHTTP(rec, r)
+
+		logger.Info("request",
+			"request_id", id,
+			"method", r.Method,
+			"path", r.URL.Path,
+			"target_url", info.targetURL,
+			"status", rec.status,
+			"duration_ms", time.Since(start).Milliseconds(),
+		)
+	})
+}
+
+func newRequestID() string {
+	var b [16]byte
+	rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// Answers every request it gets through with 200 and "ok"
+var okHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	w.Write([]byte("ok"))
+})
+
+func TestAllowCORS(t *testing.T) {
+	tests := []struct {
+		name      string
+		origins   []string
+		method    string
+		header    map[string]string
+		status    int
+		allow     string
+		preflight bool
+	}{
+		{"no origins configured", nil, http.MethodGet, map[string]string{"Origin": "https://a.example"}, 200, "", false},
+		{"same origin request", []string{"https://a.example"}, http.MethodGet, nil, 200, "", false},
+		{"allowed origin", []string{"https://a.example"}, http.MethodGet, map[string]string{"Origin": "https://a.example"}, 200, "https://a.example", false},
+		{"other origin", []string{"https://a.example"}, http.MethodGet, map[string]string{"Origin": "https://b.example"}, 200, "", false},
+		{"any origin", []string{"*"}, http.MethodPost, map[string]string{"Origin": "https://b.example"}, 200, "https://b.example", false},
+		{"preflight", []string{"https://a.example"}, http.MethodOptions, map[string]string{
+			"Origin":                         "https://a.example",
+			"Access-Control-Request-Method":  "POST",
+			"Access-Control-Request-Headers": "Content-Type",
+		}, http.StatusNoContent, "https://a.example", true},
+		{"plain OPTIONS", []string{"https://a.example"}, http.MethodOptions, map[string]string{"Origin": "https://a.example"}, 200, "https://a.example", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/scrape", nil)
+			for k, v := range tt.header {
+				req.Header.Set(k, v)
+			}
+			rec := httptest.NewRecorder()
+			allowCORS(tt.origins)(okHandler).ServeHTTP(rec, req)
+
+			if rec.Code != tt.status {
+				t.Errorf("status = %d, want %d", rec.Code, tt.status)
+			}
+			if got := rec.Header().Get("Access-Control-Allow-Origin"); got != tt.allow {
+				t.Errorf("Access-Control-Allow-Origin = %q, want %q", got, tt.allow)
+			}
+			if got := rec.Header().Get("Access-Control-Allow-Methods") != ""; got != tt.preflight {
+				t.Errorf("answered as a preflight = %v, want %v", got, tt.preflight)
+			}
+			if tt.preflight && rec.Header().Get("Access-Control-Allow-Headers") != "Content-Type" {
+				t.Errorf("Access-Control-Allow-Headers = %q, want the requested headers", rec.Header().Get("Access-Control-Allow-Headers"))
+			}
+			if req.Header.Get("Origin") != "" && len(tt.origins) > 0 && rec.Header().Get("Vary") != "Origin" {
+				t.Errorf("Vary = %q, want Origin", rec.Header().Get("Vary"))
+			}
+		})
+	}
+}
+
+func TestRequireAPIKey(t *testing.T) {
+	tests := []struct {
+		name   string
+		key    string
+		header map[string]string
+		status int
+	}{
+		{"no key configured", "", nil, http.StatusOK},
+		{"missing", "secret", nil, http.StatusUnauthorized},
+		{"X-API-Key", "secret", map[string]string{"X-API-Key": "secret"}, http.StatusOK},
+		{"wrong X-API-Key", "secret", map[string]string{"X-API-Key": "guess"}, http.StatusUnauthorized},
+		{"bearer", "secret", map[string]string{"Authorization": "Bearer secret"}, http.StatusOK},
+		{"bearer scheme in any case", "secret", map[string]string{"Authorization": "bearer secret"}, http.StatusOK},
+		{"wrong bearer", "secret", map[string]string{"Authorization": "Bearer guess"}, http.StatusUnauthorized},
+		{"other scheme", "secret", map[string]string{"Authorization": "Basic secret"}, http.StatusUnauthorized},
+		{"unrelated bearer with good X-API-Key", "secret", map[string]string{
+			"Authorization": "Bearer proxy-token",
+			"X-API-Key":     "secret",
+		}, http.StatusOK},
+		{"prefix of the key", "secret", map[string]string{"X-API-Key": "secre"}, http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/scrape", nil)
+			for k, v := range tt.header {
+				req.Header.Set(k, v)
+			}
+			rec := httptest.NewRecorder()
+			requireAPIKey(tt.key)(okHandler).ServeHTTP(rec, req)
+
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.status)
+			}
+			if tt.status == http.StatusUnauthorized {
+				if rec.Header().Get("WWW-Authenticate") != "Bearer" {
+					t.Errorf("WWW-Authenticate = %q, want Bearer", rec.Header().Get("WWW-Authenticate"))
+				}
+				assertErrorCode(t, rec, codeUnauthorized)
+			}
+		})
+	}
+}
+
+// Checks that rec holds a JSON error with code
+func assertErrorCode(t *testing.T, rec *httptest.ResponseRecorder, code string) {
+	t.Helper()
+	var body errorResponse
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("decoding error body: %v", err)
+	}
+	if body.Error.Code != code || body.Error.Status != rec.Code {
+		t.Errorf("error = %+v, want code %s and status %d", body.Error, code, rec.Code)
+	}
+}
+
+func TestLimitConcurrency(t *testing.T) {
+	const n = 2
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	blocking := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		entered <- struct{}{}
+		<-release
+	})
+
+	// The limit is shared by every handler it wraps
+	limit := limitConcurrency(n)
+	slow, fast := limit(blocking), limit(okHandler)
+
+	var wg sync.WaitGroup
+	for range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			slow.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/scrape", nil))
+		}()
+		<-entered
+	}
+
+	rec := httptest.NewRecorder()
+	fast.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/scrape/batch", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status when full = %d, want 503", rec.Code)
+	}
+	if rec.Header().Get("Retry-After") != retryAfterSeconds {
+		t.Errorf("Retry-After = %q, want %q", rec.Header().Get("Retry-After"), retryAfterSeconds)
+	}
+	assertErrorCode(t, rec, codeServerBusy)
+
+	close(release)
+	wg.Wait()
+
+	rec = httptest.NewRecorder()
+	fast.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/scrape/batch", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("status once freed = %d, want 200", rec.Code)
+	}
+}
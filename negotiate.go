@@ -0,0 +1,90 @@
+package main
+
+import (
+	"encoding/json"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Reports whether the client's Accept header prefers plain text over JSON.
+// JSON wins ties and is the default when Accept is missing.
+func prefersPlainText(accept string) bool {
+	if accept == "" {
+		return false
+	}
+
+	// The quality given to each of the two types, -1 if not acceptable
+	textQ, jsonQ := -1.0, -1.0
+	// How specific the range that set each quality was, as the most
+	// specific match decides
+	textRank, jsonRank := -1, -1
+
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(v, 64); err != nil {
+				continue
+			}
+		}
+
+		switch mediaType {
+		case "text/plain":
+			textQ, textRank = rankedQ(textQ, textRank, q, 2)
+		case "text/*":
+			textQ, textRank = rankedQ(textQ, textRank, q, 1)
+		case "application/json":
+			jsonQ, jsonRank = rankedQ(jsonQ, jsonRank, q, 2)
+		case "application/*":
+			jsonQ, jsonRank = rankedQ(jsonQ, jsonRank, q, 1)
+		case "*/*":
+			textQ, textRank = rankedQ(textQ, textRank, q, 0)
+			jsonQ, jsonRank = rankedQ(jsonQ, jsonRank, q, 0)
+		}
+	}
+	return textQ > 0 && textQ > jsonQ
+}
+
+// Returns the quality and rank to keep after seeing q from a range of the
+// given rank, preferring the more specific range
+func rankedQ(q float64, rank int, newQ float64, newRank int) (float64, int) {
+	if newRank > rank {
+		return newQ, newRank
+	}
+	return q, rank
+}
+
+// Returns the content of resp as plain text, or false if the format has no
+// plain text form
+func plainText(resp scrapeResponse, format string) (string, bool) {
+	switch format {
+	case "", "text", "markdown":
+		return resp.Content, true
+	case "paragraphs":
+		return strings.Join(resp.Paragraphs, "\n\n"), true
+	case "links":
+		return strings.Join(resp.Links, "\n"), true
+	}
+	return "", false
+}
+
+// Writes resp as plain text if the client asked for it and the format
+// allows, or as JSON otherwise
+func writeScrapeResponse(w http.ResponseWriter, r *http.Request, resp scrapeResponse, format string) {
+	w.Header().Add("Vary", "Accept")
+	if prefersPlainText(r.Header.Get("Accept")) {
+		if text, ok := plainText(resp, format); ok {
+			w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+			w.Write([]byte(text))
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
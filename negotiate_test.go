@@ -0,0 +1,68 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPrefersPlainText(t *testing.T) {
+	tests := []struct {
+		accept string
+		want   bool
+	}{
+		{"", false},
+		{"*/*", false},
+		{"text/plain", true},
+		{"application/json", false},
+		{"text/plain, application/json", false},
+		{"text/plain, application/json;q=0.9", true},
+		{"text/*, application/json;q=0.5", true},
+		{"text/plain;q=0, */*", false},
+		{"text/html, */*;q=0.1", false},
+		{"text/plain;q=0.5, */*;q=0.9", false},
+		{"application/json;q=0, text/*;q=0.1", true},
+	}
+	for _, tt := range tests {
+		if got := prefersPlainText(tt.accept); got != tt.want {
+			t.Errorf("prefersPlainText(%q) = %v, want %v", tt.accept, got, tt.want)
+		}
+	}
+}
+
+func TestWriteScrapeResponse(t *testing.T) {
+	resp := scrapeResponse{Content: "hello", Paragraphs: []string{"one", "two"}, Links: []string{"https://a.example", "https://b.example"}}
+	tests := []struct {
+		name        string
+		accept      string
+		format      string
+		contentType string
+		body        string
+	}{
+		{"json by default", "", "text", "application/json", ""},
+		{"text", "text/plain", "text", "text/plain; charset=utf-8", "hello"},
+		{"paragraphs", "text/plain", "paragraphs", "text/plain; charset=utf-8", "one\n\ntwo"},
+		{"links", "text/plain", "links", "text/plain; charset=utf-8", "https://a.example\nhttps://b.example"},
+		{"no plain text form", "text/plain", "metadata", "application/json", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/scrape", nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			rec := httptest.NewRecorder()
+			writeScrapeResponse(rec, req, resp, tt.format)
+
+			if got := rec.Header().Get("Content-Type"); got != tt.contentType {
+				t.Errorf("Content-Type = %q, want %q", got, tt.contentType)
+			}
+			if rec.Header().Get("Vary") != "Accept" {
+				t.Errorf("Vary = %q, want Accept", rec.Header().Get("Vary"))
+			}
+			if tt.body != "" && rec.Body.String() != tt.body {
+				t.Errorf("body = %q, want %q", rec.Body.String(), tt.body)
+			}
+		})
+	}
+}
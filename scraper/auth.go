@@ -0,0 +1,15 @@
+package scraper
+
+import "encoding/base64"
+
+// Sends HTTP basic auth credentials with every request. The Authorization
+// header is not forwarded if a redirect leaves the original domain.
+func WithBasicAuth(user, pass string) Option {
+	creds := base64.StdEncoding.EncodeToString([]byte(user + ":" + pass))
+	return WithHeader("Authorization", "Basic "+creds)
+}
+
+// Sends a bearer token with every request
+func WithBearerToken(token string) Option {
+	return WithHeader("Authorization", "Bearer "+token)
+}
@@ -0,0 +1,101 @@
+package scraper
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// The outcome of scraping one URL in a batch
+type BatchResult struct {
+	URL     string
+	Content string
+	Err     error
+	// How long fetching and extracting the page took, zero if it was never
+	// started
+	Duration time.Duration
+}
+
+// Extracts the text of each URL, fetching up to concurrency pages at once.
+// Results are in the same order as urls, and a failure only affects its
+// own entry. Once ctx is done, in-flight fetches are aborted and URLs not
+// yet done fail with ctx.Err(), while finished ones keep their results.
+func ScrapeBatch(ctx context.Context, urls []string, concurrency int) []BatchResult {
+	return defaultScraper.ScrapeBatch(ctx, urls, concurrency)
+}
+
+// Extracts the text of each URL, fetching up to concurrency pages at once
+func (s *Scraper) ScrapeBatch(ctx context.Context, urls []string, concurrency int) []BatchResult {
+	results := make([]BatchResult, len(urls))
+	s.scrapeBatch(ctx, urls, concurrency, func(i int, r BatchResult) {
+		results[i] = r
+	})
+	return results
+}
+
+// Extracts the text of each URL like ScrapeBatch, but sends each result on
+// the returned channel as soon as it's done, in the order they finish. The
+// channel is closed when every URL is done or ctx is done.
+func ScrapeBatchStream(ctx context.Context, urls []string, concurrency int) <-chan BatchResult {
+	return defaultScraper.ScrapeBatchStream(ctx, urls, concurrency)
+}
+
+// Extracts the text of each URL, sending each result on the returned
+// channel as soon as it's done. The batch waits while the channel is full,
+// so a slow consumer holds it back rather than letting results pile up.
+func (s *Scraper) ScrapeBatchStream(ctx context.Context, urls []string, concurrency int) <-chan BatchResult {
+	ch := make(chan BatchResult)
+	go func() {
+		defer close(ch)
+		s.scrapeBatch(ctx, urls, concurrency, func(_ int, r BatchResult) {
+			select {
+			case ch <- r:
+			case <-ctx.Done():
+			}
+		})
+	}()
+	return ch
+}
+
+// Runs a batch, calling done with the index and result of each URL, from
+// the worker that scraped it or, for URLs never started because ctx was
+// done, from the calling goroutine
+func (s *Scraper) scrapeBatch(ctx context.Context, urls []string, concurrency int, done func(int, BatchResult)) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+	for range min(concurrency, len(urls)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				start := time.Now()
+				text, err := s.ExtractTextContext(ctx, urls[i])
+				if err != nil && ctx.Err() != nil {
+					err = ctx.Err()
+				}
+				done(i, BatchResult{URL: urls[i], Content: text, Err: err, Duration: time.Since(start)})
+			}
+		}()
+	}
+
+	next := 0
+feed:
+	for ; next < len(urls); next++ {
+		select {
+		case jobs <- next:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	for i := next; i < len(urls); i++ {
+		done(i, BatchResult{URL: urls[i], Err: ctx.Err()})
+	}
+}
@@ -0,0 +1,192 @@
+package scraper
+
+import (
+	"context"
+	"net/url"
+	"sort"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// One step of a page's breadcrumb trail
+type Breadcrumb struct {
+	Name string `json:"name"`
+	// Absolute URL of the step, empty for steps that aren't links, such as
+	// the current page
+	URL string `json:"url"`
+}
+
+// Extracts a page's breadcrumb trail, from the site root down to the page.
+// Returns an empty slice if the page has none.
+func ExtractBreadcrumbs(u string) ([]Breadcrumb, error) {
+	return defaultScraper.ExtractBreadcrumbs(context.Background(), u)
+}
+
+// Extracts the breadcrumb trail of the page at u, from a JSON-LD
+// BreadcrumbList if it has one, or else from breadcrumb navigation markup
+// such as <nav aria-label="breadcrumb">
+func (s *Scraper) ExtractBreadcrumbs(ctx context.Context, u string) ([]Breadcrumb, error) {
+	doc, pageURL, err := s.fetchDocument(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+	return breadcrumbsFromHTML(doc, pageURL, s.extract), nil
+}
+
+func breadcrumbsFromHTML(doc *html.Node, pageURL *url.URL, o *extractOptions) []Breadcrumb {
+	base := baseURL(doc, pageURL)
+	for _, obj := range jsonLDFromHTML(doc) {
+		if crumbs := jsonLDBreadcrumbs(obj, base); len(crumbs) > 0 {
+			return crumbs
+		}
+	}
+	if n := findBreadcrumbNav(doc); n != nil {
+		return navBreadcrumbs(n, base, o)
+	}
+	return []Breadcrumb{}
+}
+
+// Reads the first BreadcrumbList in a JSON-LD object, including the objects
+// of an @graph, ordered by position
+func jsonLDBreadcrumbs(obj map[string]interface{}, base *url.URL) []Breadcrumb {
+	if !hasJSONLDType(obj, "BreadcrumbList") {
+		graph, _ := obj["@graph"].([]interface{})
+		for _, item := range graph {
+			if o, ok := item.(map[string]interface{}); ok {
+				if crumbs := jsonLDBreadcrumbs(o, base); len(crumbs) > 0 {
+					return crumbs
+				}
+			}
+		}
+		return nil
+	}
+
+	elements, _ := obj["itemListElement"].([]interface{})
+	type positioned struct {
+		Breadcrumb
+		position float64
+	}
+	var items []positioned
+	for _, e := range elements {
+		li, ok := e.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		// item is either the step's URL or an object describing it
+		name, _ := li["name"].(string)
+		var link string
+		switch item := li["item"].(type) {
+		case string:
+			link = item
+		case map[string]interface{}:
+			if name == "" {
+				name, _ = item["name"].(string)
+			}
+			if link, _ = item["@id"].(string); link == "" {
+				link, _ = item["url"].(string)
+			}
+		}
+
+		crumb := Breadcrumb{Name: normalizeSpace(name)}
+		if abs, ok := resolveLink(base, link); ok {
+			crumb.URL = abs
+		}
+		if crumb.Name == "" && crumb.URL == "" {
+			continue
+		}
+		position, _ := li["position"].(float64)
+		items = append(items, positioned{crumb, position})
+	}
+
+	sort.SliceStable(items, func(i, j int) bool { return items[i].position < items[j].position })
+	crumbs := make([]Breadcrumb, len(items))
+	for i, item := range items {
+		crumbs[i] = item.Breadcrumb
+	}
+	return crumbs
+}
+
+// Reports whether a JSON-LD object's @type, a string or a list of them,
+// includes typ
+func hasJSONLDType(obj map[string]interface{}, typ string) bool {
+	switch t := obj["@type"].(type) {
+	case string:
+		return t == typ
+	case []interface{}:
+		for _, v := range t {
+			if v == typ {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Finds the first element marked up as breadcrumbs: labelled so with
+// aria-label, given a breadcrumb class, or a microdata BreadcrumbList
+func findBreadcrumbNav(n *html.Node) *html.Node {
+	if n.Type == html.ElementNode {
+		if strings.Contains(strings.ToLower(getAttr(n, "aria-label")), "breadcrumb") ||
+			strings.HasSuffix(getAttr(n, "itemtype"), "/BreadcrumbList") ||
+			hasToken(getAttr(n, "class"), "breadcrumb") || hasToken(getAttr(n, "class"), "breadcrumbs") {
+			return n
+		}
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if found := findBreadcrumbNav(c); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// Reads the steps of breadcrumb markup: one per list item, linked if the
+// item holds a link, or one per link if it isn't a list
+func navBreadcrumbs(nav *html.Node, base *url.URL, o *extractOptions) []Breadcrumb {
+	var items []*html.Node
+	var findItems func(*html.Node)
+	findItems = func(n *html.Node) {
+		if isElement(n, "li") {
+			items = append(items, n)
+			return
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			findItems(c)
+		}
+	}
+	findItems(nav)
+
+	if len(items) == 0 {
+		var findLinks func(*html.Node)
+		findLinks = func(n *html.Node) {
+			if isElement(n, "a") {
+				items = append(items, n)
+				return
+			}
+			for c := n.FirstChild; c != nil; c = c.NextSibling {
+				findLinks(c)
+			}
+		}
+		findLinks(nav)
+	}
+
+	crumbs := []Breadcrumb{}
+	for _, item := range items {
+		crumb := Breadcrumb{Name: textFromNode(item, o)}
+		link := item
+		if !isElement(item, "a") {
+			link = findElement(item, "a")
+		}
+		if link != nil {
+			if abs, ok := resolveLink(base, getAttr(link, "href")); ok {
+				crumb.URL = abs
+			}
+		}
+		if crumb.Name != "" {
+			crumbs = append(crumbs, crumb)
+		}
+	}
+	return crumbs
+}
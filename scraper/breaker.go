@@ -0,0 +1,95 @@
+package scraper
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Stops sending requests to a host after failures requests to it in a row
+// have failed, with a connection error or a 5xx status. For cooldown after
+// that, its requests fail straight away with ErrCircuitOpen. Then a single
+// request is let through to probe the host: if it succeeds, requests flow
+// again, and if not, the host is cut off for another cooldown. Retries
+// count as requests.
+func WithCircuitBreaker(failures int, cooldown time.Duration) Option {
+	return func(s *Scraper) {
+		if failures < 1 {
+			s.optErrs = append(s.optErrs, fmt.Errorf("circuit breaker failures must be at least 1, got %d", failures))
+			return
+		}
+		s.breakers = &hostBreakers{
+			failures: failures,
+			cooldown: cooldown,
+			hosts:    make(map[string]*breaker),
+		}
+	}
+}
+
+// Circuit breakers, one per host that has failed lately
+type hostBreakers struct {
+	failures int
+	cooldown time.Duration
+
+	mu    sync.Mutex
+	hosts map[string]*breaker
+}
+
+type breaker struct {
+	// Failures in a row. The breaker is open once this reaches the limit.
+	failures int
+	// When the next probe may go out, while open
+	retryAt time.Time
+	// A probe is in flight, so other requests are still turned away
+	probing bool
+}
+
+// Returns ErrCircuitOpen if requests to host are cut off. Otherwise the
+// request may go ahead, and its outcome must be passed to record or
+// release.
+func (h *hostBreakers) allow(host string) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	b, ok := h.hosts[host]
+	if !ok || b.failures < h.failures {
+		return nil
+	}
+	if b.probing || time.Now().Before(b.retryAt) {
+		return fmt.Errorf("%w: %s", ErrCircuitOpen, host)
+	}
+	b.probing = true
+	return nil
+}
+
+// Records the outcome of a request allowed to host
+func (h *hostBreakers) record(host string, failed bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if !failed {
+		delete(h.hosts, host)
+		return
+	}
+	b, ok := h.hosts[host]
+	if !ok {
+		b = &breaker{}
+		h.hosts[host] = b
+	}
+	b.probing = false
+	b.failures++
+	if b.failures >= h.failures {
+		b.retryAt = time.Now().Add(h.cooldown)
+	}
+}
+
+// Records that a request allowed to host was abandoned, saying nothing
+// about the host
+func (h *hostBreakers) release(host string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if b, ok := h.hosts[host]; ok {
+		b.probing = false
+	}
+}
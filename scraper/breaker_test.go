@@ -0,0 +1,109 @@
+package scraper_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/charlescqian/go-scrape/scraper"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	const cooldown = 20 * time.Millisecond
+	type step struct {
+		// Sleep past the cooldown first
+		wait bool
+		// What the host answers, if the request gets to it
+		status int
+		// Whether the request should be turned away without reaching it
+		open bool
+	}
+	tests := []struct {
+		name  string
+		steps []step
+	}{
+		{"opens after failures in a row", []step{
+			{status: 500}, {status: 500}, {status: 200, open: true},
+		}},
+		{"success resets the count", []step{
+			{status: 500}, {status: 200}, {status: 500}, {status: 200},
+		}},
+		{"client errors don't count", []step{
+			{status: 404}, {status: 404}, {status: 200},
+		}},
+		{"probe closes it", []step{
+			{status: 500}, {status: 500}, {wait: true, status: 200}, {status: 200},
+		}},
+		{"failed probe reopens it", []step{
+			{status: 500}, {status: 500}, {wait: true, status: 500}, {status: 200, open: true},
+			{wait: true, status: 200},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var status atomic.Int64
+			var hits atomic.Int64
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				hits.Add(1)
+				w.Header().Set("Content-Type", "text/html")
+				w.WriteHeader(int(status.Load()))
+				w.Write([]byte("<p>ok</p>"))
+			}))
+			defer srv.Close()
+
+			s, err := scraper.NewScraper(scraper.WithCircuitBreaker(2, cooldown))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i, st := range tt.steps {
+				if st.wait {
+					time.Sleep(cooldown + 10*time.Millisecond)
+				}
+				status.Store(int64(st.status))
+				before := hits.Load()
+				_, err := s.ExtractText(srv.URL)
+
+				if open := errors.Is(err, scraper.ErrCircuitOpen); open != st.open {
+					t.Fatalf("step %d: error = %v, want open %v", i, err, st.open)
+				}
+				if reached := hits.Load() > before; reached == st.open {
+					t.Errorf("step %d: reached the host = %v, want %v", i, reached, !st.open)
+				}
+			}
+		})
+	}
+}
+
+// Each host has its own breaker
+func TestCircuitBreakerPerHost(t *testing.T) {
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "down", http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+	healthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte("<p>ok</p>"))
+	}))
+	defer healthy.Close()
+
+	s, err := scraper.NewScraper(scraper.WithCircuitBreaker(1, time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.ExtractText(failing.URL)
+	if _, err := s.ExtractText(failing.URL); !errors.Is(err, scraper.ErrCircuitOpen) {
+		t.Errorf("failing host: error = %v, want ErrCircuitOpen", err)
+	}
+	if _, err := s.ExtractText(healthy.URL); err != nil {
+		t.Errorf("healthy host: error = %v, want nil", err)
+	}
+}
+
+func TestCircuitBreakerOptionErrors(t *testing.T) {
+	if _, err := scraper.NewScraper(scraper.WithCircuitBreaker(0, time.Second)); err == nil {
+		t.Error("NewScraper(WithCircuitBreaker(0, ...)) succeeded, want an error")
+	}
+}
@@ -0,0 +1,133 @@
+package scraper
+
+import (
+	"sync"
+	"time"
+)
+
+// The cache is swept for expired entries every ttl, but no more often than
+// this
+const minSweepInterval = time.Minute
+
+// Extracted text of recently scraped pages, keyed by requested URL and
+// per-call headers, as from requestKey
+type textCache struct {
+	ttl time.Duration
+
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+
+	// Closed to stop the janitor
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+type cacheEntry struct {
+	text     string
+	finalURL string
+	expires  time.Time
+
+	// Validators from the response, for revalidating once expired
+	etag         string
+	lastModified string
+}
+
+// Keeps the text extracted from each URL for ttl, so repeated requests for
+// the same URL within that window don't hit the network. Calls with
+// different headers from ContextWithHeader are cached apart. After that, pages
+// that sent an ETag or Last-Modified header are revalidated with a
+// conditional request, and the cached text reused if they haven't changed.
+//
+// Expired entries are swept out in the background, by a goroutine that
+// runs until the Scraper is closed with Close.
+func WithCache(ttl time.Duration) Option {
+	return func(s *Scraper) {
+		s.cache = &textCache{ttl: ttl, entries: make(map[string]cacheEntry)}
+	}
+}
+
+// Drops everything from the Scraper's cache, if it has one
+func (s *Scraper) ClearCache() {
+	if s.cache != nil {
+		s.cache.clear()
+	}
+}
+
+// Looks up u, reporting whether the entry is still fresh. Expired entries
+// are kept only if they can be revalidated.
+func (c *textCache) get(u string) (e cacheEntry, fresh bool, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok = c.entries[u]
+	if !ok {
+		return cacheEntry{}, false, false
+	}
+	if time.Now().After(e.expires) {
+		if e.etag == "" && e.lastModified == "" {
+			delete(c.entries, u)
+			return cacheEntry{}, false, false
+		}
+		return e, false, true
+	}
+	return e, true, true
+}
+
+func (c *textCache) set(u, text, finalURL, etag, lastModified string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[u] = cacheEntry{
+		text:         text,
+		finalURL:     finalURL,
+		expires:      time.Now().Add(c.ttl),
+		etag:         etag,
+		lastModified: lastModified,
+	}
+}
+
+// Starts the goroutine sweeping out expired entries, until stopJanitor
+func (c *textCache) startJanitor() {
+	c.stop = make(chan struct{})
+	go func() {
+		t := time.NewTicker(max(c.ttl, minSweepInterval))
+		defer t.Stop()
+		for {
+			select {
+			case <-c.stop:
+				return
+			case now := <-t.C:
+				c.sweep(now)
+			}
+		}
+	}()
+}
+
+// Stops the janitor. Safe to call more than once.
+func (c *textCache) stopJanitor() {
+	c.stopOnce.Do(func() { close(c.stop) })
+}
+
+// Drops the entries that expired by now. Those that can be revalidated are
+// kept for another ttl, in case they're asked for again.
+func (c *textCache) sweep(now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for u, e := range c.entries {
+		expires := e.expires
+		if e.etag != "" || e.lastModified != "" {
+			expires = expires.Add(c.ttl)
+		}
+		if now.After(expires) {
+			delete(c.entries, u)
+		}
+	}
+}
+
+func (c *textCache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries = make(map[string]cacheEntry)
+}
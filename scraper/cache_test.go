@@ -0,0 +1,54 @@
+package scraper_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/charlescqian/go-scrape/scraper"
+)
+
+func TestCacheKeepsPerCallHeadersApart(t *testing.T) {
+	var hits atomic.Int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprintf(w, "<p>hello %s</p>", r.Header.Get("Authorization"))
+	}))
+	defer srv.Close()
+
+	s, err := scraper.NewScraper(scraper.WithCache(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	alice := scraper.ContextWithHeader(context.Background(), "Authorization", "alice")
+	bob := scraper.ContextWithHeader(context.Background(), "Authorization", "bob")
+	for _, c := range []struct {
+		ctx  context.Context
+		want string
+		hits int64
+	}{
+		{alice, "hello alice", 1},
+		{bob, "hello bob", 2},
+		{context.Background(), "hello", 3},
+		{alice, "hello alice", 3},
+		{bob, "hello bob", 3},
+	} {
+		text, err := s.ExtractTextContext(c.ctx, srv.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if text != c.want {
+			t.Errorf("ExtractText() = %q, want %q", text, c.want)
+		}
+		if n := hits.Load(); n != c.hits {
+			t.Errorf("after %q, server got %d requests, want %d", c.want, n, c.hits)
+		}
+	}
+}
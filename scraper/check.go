@@ -0,0 +1,66 @@
+package scraper
+
+import (
+	"context"
+	"net/http"
+)
+
+// What CheckURL found out about a URL without downloading it
+type CheckResult struct {
+	// URL the response came from, after redirects
+	URL         string
+	StatusCode  int
+	ContentType string
+	// -1 if the server didn't say
+	ContentLength int64
+}
+
+// Checks whether u is reachable without downloading its body
+func CheckURL(u string) (CheckResult, error) {
+	return defaultScraper.CheckURL(context.Background(), u)
+}
+
+// Checks whether u is reachable without downloading its body. Sends a HEAD
+// request, falling back to a GET whose body is discarded if the server
+// doesn't support HEAD. The result holds whatever status the server gave;
+// only failing to get a response at all is an error.
+func (s *Scraper) CheckURL(ctx context.Context, u string) (CheckResult, error) {
+	resp, err := s.check(ctx, http.MethodHead, u)
+	if err != nil {
+		return CheckResult{}, err
+	}
+	if resp.StatusCode == http.StatusMethodNotAllowed || resp.StatusCode == http.StatusNotImplemented {
+		resp, err = s.check(ctx, http.MethodGet, u)
+		if err != nil {
+			return CheckResult{}, err
+		}
+	}
+
+	return CheckResult{
+		URL:           resp.Request.URL.String(),
+		StatusCode:    resp.StatusCode,
+		ContentType:   resp.Header.Get("Content-Type"),
+		ContentLength: resp.ContentLength,
+	}, nil
+}
+
+// Sends a request for u and closes the response body unread
+func (s *Scraper) check(ctx context.Context, method, u string) (*http.Response, error) {
+	req, err := s.newRequest(ctx, method, u)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.robots != nil {
+		if err := s.checkRobots(req); err != nil {
+			return nil, err
+		}
+	}
+
+	resp, err := s.do(req)
+	if err != nil {
+		return nil, networkError(u, err)
+	}
+	resp.Body.Close()
+	return resp, nil
+}
@@ -0,0 +1,155 @@
+package scraper
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Splits text into chunks of at most maxChars characters, e.g. to fit a
+// model's context window. Chunks break between paragraphs where possible,
+// then between sentences, then between words. A single word longer than
+// maxChars is the only thing ever cut.
+func ChunkText(text string, maxChars int) []string {
+	return ChunkTextWithOverlap(text, maxChars, 0)
+}
+
+// Splits text like ChunkText, but starts each chunk after the first with up
+// to overlap characters of whole words from the end of the chunk before it,
+// so context isn't lost at the boundaries
+func ChunkTextWithOverlap(text string, maxChars, overlap int) []string {
+	if maxChars <= 0 {
+		return nil
+	}
+
+	var chunks []string
+	var cur strings.Builder
+	// Characters in cur, and how many of them are overlap from the chunk
+	// before
+	curLen, carried := 0, 0
+
+	flush := func() {
+		chunk := cur.String()
+		chunks = append(chunks, chunk)
+		cur.Reset()
+
+		tail := wordsTail(chunk, overlap)
+		cur.WriteString(tail)
+		curLen = utf8.RuneCountInString(tail)
+		carried = curLen
+	}
+
+	for _, p := range chunkPieces(text, maxChars) {
+		n := utf8.RuneCountInString(p.text)
+		sep := p.sep
+		if curLen > carried && (p.fresh || curLen+utf8.RuneCountInString(sep)+n > maxChars) {
+			flush()
+		}
+		// Drop the overlap rather than overflow the chunk
+		if curLen > 0 && curLen+utf8.RuneCountInString(sep)+n > maxChars {
+			cur.Reset()
+			curLen, carried = 0, 0
+		}
+		if curLen > 0 {
+			cur.WriteString(sep)
+			curLen += utf8.RuneCountInString(sep)
+		}
+		cur.WriteString(p.text)
+		curLen += n
+	}
+
+	// A chunk holding nothing but the overlap adds nothing new
+	if curLen > carried {
+		chunks = append(chunks, cur.String())
+	}
+	return chunks
+}
+
+// A unit of text no larger than a chunk, and what joins it to the unit
+// before
+type chunkPiece struct {
+	text string
+	sep  string
+	// Start a new chunk here rather than cut the sentence that follows
+	// somewhere in its middle
+	fresh bool
+}
+
+// Breaks text into paragraphs, paragraphs longer than maxChars into
+// sentences, and sentences longer than that into words
+func chunkPieces(text string, maxChars int) []chunkPiece {
+	var pieces []chunkPiece
+	for _, para := range strings.Split(text, "\n\n") {
+		sep := "\n\n"
+		for _, sentence := range sentences(para) {
+			if utf8.RuneCountInString(sentence) <= maxChars {
+				pieces = append(pieces, chunkPiece{text: sentence, sep: sep})
+				sep = " "
+				continue
+			}
+			fresh := true
+			for _, word := range strings.Fields(sentence) {
+				for _, part := range splitRunes(word, maxChars) {
+					pieces = append(pieces, chunkPiece{text: part, sep: sep, fresh: fresh})
+					sep, fresh = " ", false
+				}
+			}
+		}
+	}
+	return pieces
+}
+
+// Splits a paragraph after words ending in sentence punctuation,
+// collapsing its whitespace along the way
+func sentences(para string) []string {
+	var out, words []string
+	for _, w := range strings.Fields(para) {
+		words = append(words, w)
+		end := strings.TrimRight(w, `"')]}»”’`)
+		if strings.HasSuffix(end, ".") || strings.HasSuffix(end, "!") || strings.HasSuffix(end, "?") {
+			out = append(out, strings.Join(words, " "))
+			words = nil
+		}
+	}
+	if len(words) > 0 {
+		out = append(out, strings.Join(words, " "))
+	}
+	return out
+}
+
+// Cuts s into parts of at most n characters
+func splitRunes(s string, n int) []string {
+	var parts []string
+	for utf8.RuneCountInString(s) > n {
+		i := 0
+		for range n {
+			_, size := utf8.DecodeRuneInString(s[i:])
+			i += size
+		}
+		parts = append(parts, s[:i])
+		s = s[i:]
+	}
+	return append(parts, s)
+}
+
+// Returns the longest run of whole words at the end of s that is at most n
+// characters long
+func wordsTail(s string, n int) string {
+	if n <= 0 {
+		return ""
+	}
+	words := strings.Fields(s)
+	length := 0
+	i := len(words)
+	for i > 0 {
+		l := utf8.RuneCountInString(words[i-1])
+		if length > 0 {
+			l++
+		}
+		if length+l > n {
+			break
+		}
+		length += l
+		i--
+	}
+	return strings.Join(words[i:], " ")
+}
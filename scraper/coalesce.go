@@ -0,0 +1,127 @@
+package scraper
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// Shares one fetch of a page between concurrent calls extracting its text,
+// so callers asking for the same URL at the same time only download it once
+type flightGroup struct {
+	group singleflight.Group
+
+	mu    sync.Mutex
+	calls map[string]*flightCall
+}
+
+// The callers waiting on one fetch. The fetch runs under its own context,
+// cancelled once every caller has given up on it, so one caller's
+// cancellation doesn't fail the others.
+type flightCall struct {
+	ctx     context.Context
+	cancel  context.CancelFunc
+	waiters int
+}
+
+// What a shared fetch hands to each of its callers
+type flightResult struct {
+	text     string
+	info     ResponseInfo
+	warnings []Warning
+}
+
+// Extracts the text of the page at u like ExtractWithResponseInfo, sharing
+// the fetch with any concurrent call for the same URL and headers. Every
+// caller gets the same result, error and warnings.
+func (s *Scraper) extractShared(ctx context.Context, u string) (string, ResponseInfo, error) {
+	key := requestKey(ctx, u)
+	call := s.flights.join(ctx, key)
+	defer s.flights.leave(key, call)
+
+	ch := s.flights.group.DoChan(key, func() (interface{}, error) {
+		defer s.flights.finish(key, call)
+
+		fctx, sink := withWarnings(call.ctx)
+		text, info, err := s.ExtractWithResponseInfo(fctx, u)
+		return &flightResult{text: text, info: info, warnings: sink.warnings}, err
+	})
+
+	select {
+	case <-ctx.Done():
+		return "", ResponseInfo{}, ctx.Err()
+	case r := <-ch:
+		res := r.Val.(*flightResult)
+		for i := range res.warnings {
+			addWarning(ctx, &res.warnings[i])
+		}
+		return res.text, res.info, r.Err
+	}
+}
+
+// Registers a caller for the fetch of key, starting a new one if none is
+// running
+func (f *flightGroup) join(ctx context.Context, key string) *flightCall {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.calls == nil {
+		f.calls = make(map[string]*flightCall)
+	}
+	call, ok := f.calls[key]
+	if !ok {
+		// Context values, such as headers, still apply to the fetch
+		fctx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		call = &flightCall{ctx: fctx, cancel: cancel}
+		f.calls[key] = call
+	}
+	call.waiters++
+	return call
+}
+
+// Unregisters a caller, abandoning the fetch if it was the last one
+// waiting
+func (f *flightGroup) leave(key string, call *flightCall) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	call.waiters--
+	if call.waiters > 0 {
+		return
+	}
+	call.cancel()
+	if f.calls[key] == call {
+		// Still running, so the next caller must start over rather than
+		// join a cancelled fetch
+		delete(f.calls, key)
+		f.group.Forget(key)
+	}
+}
+
+// Called when the fetch of key is done, so later callers fetch afresh
+func (f *flightGroup) finish(key string, call *flightCall) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.calls[key] == call {
+		delete(f.calls, key)
+	}
+}
+
+// Identifies a fetch by its URL and any per-call headers on ctx, as calls
+// sending different headers may get different pages. Keys both shared
+// fetches and the cache.
+func requestKey(ctx context.Context, u string) string {
+	h, ok := ctx.Value(headerKey{}).(http.Header)
+	if !ok || len(h) == 0 {
+		return u
+	}
+	var b strings.Builder
+	b.WriteString(u)
+	b.WriteByte('\n')
+	h.Write(&b)
+	return b.String()
+}
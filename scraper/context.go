@@ -0,0 +1,40 @@
+package scraper
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+type headerKey struct{}
+
+// Returns a copy of ctx that makes requests made with it send the given
+// header, overriding the Scraper's own value for that key
+func ContextWithHeader(ctx context.Context, key, value string) context.Context {
+	h := http.Header{}
+	if prev, ok := ctx.Value(headerKey{}).(http.Header); ok {
+		h = prev.Clone()
+	}
+	h.Set(key, value)
+	return context.WithValue(ctx, headerKey{}, h)
+}
+
+// Returns a copy of ctx that makes requests made with it conditional on the
+// page having changed, given the ETag and Last-Modified time from when it
+// was last fetched. Either may be left empty. An unchanged page then fails
+// with ErrNotModified instead of being downloaded again.
+func ContextWithConditional(ctx context.Context, etag string, modifiedSince time.Time) context.Context {
+	if etag != "" {
+		ctx = ContextWithHeader(ctx, "If-None-Match", etag)
+	}
+	if !modifiedSince.IsZero() {
+		ctx = ContextWithHeader(ctx, "If-Modified-Since", modifiedSince.UTC().Format(http.TimeFormat))
+	}
+	return ctx
+}
+
+// Reports whether ctx already makes requests conditional
+func hasConditional(ctx context.Context) bool {
+	h, ok := ctx.Value(headerKey{}).(http.Header)
+	return ok && (h.Get("If-None-Match") != "" || h.Get("If-Modified-Since") != "")
+}
@@ -0,0 +1,34 @@
+package scraper
+
+import (
+	"errors"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+
+	"golang.org/x/net/publicsuffix"
+)
+
+// Keeps cookies set by responses and sends them back on later requests, so
+// a session survives across calls on the same Scraper
+func WithCookieJar() Option {
+	return func(s *Scraper) {
+		// cookiejar.New never fails
+		s.jar, _ = cookiejar.New(&cookiejar.Options{PublicSuffixList: publicsuffix.List})
+	}
+}
+
+// Seeds the cookie jar with cookies for u, e.g. a session cookie obtained
+// by logging in elsewhere. Requires WithCookieJar.
+func (s *Scraper) SetCookies(u string, cookies []*http.Cookie) error {
+	if s.jar == nil {
+		return errors.New("scraper has no cookie jar, use WithCookieJar")
+	}
+
+	parsed, err := url.Parse(u)
+	if err != nil {
+		return err
+	}
+	s.jar.SetCookies(parsed, cookies)
+	return nil
+}
@@ -0,0 +1,268 @@
+package scraper
+
+import (
+	"context"
+	"net/url"
+	"regexp"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Controls how far and how fast Crawl goes
+type CrawlOptions struct {
+	// How many links away from the start page to follow. 0 only scrapes the
+	// start page itself.
+	MaxDepth int
+	// Only follow links to the start page's host
+	SameHost bool
+	// How many pages to fetch at once. Defaults to 1.
+	Concurrency int
+	// If set, only follow links whose absolute URL matches, e.g.
+	// regexp.MustCompile(`^https://example\.com/blog/`). Start pages are
+	// always fetched.
+	AllowPattern *regexp.Regexp
+	// If set, never follow links whose absolute URL matches
+	DenyPattern *regexp.Regexp
+	// Stop after fetching this many pages, counting failed ones. 0 means no
+	// limit.
+	MaxPages int
+	// Stop once the crawl has run this long, abandoning pages still being
+	// fetched. 0 means no limit.
+	MaxDuration time.Duration
+}
+
+// Scrapes startURL and the pages it links to, breadth first, returning the
+// text of each page keyed by URL. Pages that fail to load are left out.
+func Crawl(startURL string, opts CrawlOptions) (map[string]string, error) {
+	return defaultScraper.Crawl(context.Background(), startURL, opts)
+}
+
+// Scrapes startURL and the pages it links to, breadth first. Requests go
+// through the Scraper, so its rate limit and robots.txt settings apply.
+func (s *Scraper) Crawl(ctx context.Context, startURL string, opts CrawlOptions) (map[string]string, error) {
+	return s.CrawlSeeds(ctx, []string{startURL}, opts)
+}
+
+// Crawls from several start pages at once, as if they were all linked from
+// one page at depth -1: no page is fetched twice, however many seeds reach
+// it. With SameHost, links to the host of any seed are followed.
+func CrawlSeeds(seeds []string, opts CrawlOptions) (map[string]string, error) {
+	return defaultScraper.CrawlSeeds(context.Background(), seeds, opts)
+}
+
+// Crawls from several start pages at once, merging the results into one map
+func (s *Scraper) CrawlSeeds(ctx context.Context, seeds []string, opts CrawlOptions) (map[string]string, error) {
+	results := make(map[string]string)
+	err := s.crawl(ctx, seeds, opts, func(u string, p crawledPage) {
+		if p.err == nil {
+			results[u] = p.text
+		}
+	}, nil)
+	return results, err
+}
+
+// One page reached by CrawlStream or CrawlToSink
+type CrawlResult struct {
+	URL  string
+	Text string
+	// Set if the page couldn't be scraped, in which case Text is empty
+	Err error
+}
+
+// Crawls like Crawl, but sends each page on the returned channel as soon as
+// it has been scraped, including pages that failed. The channel is closed
+// when the crawl is finished or ctx is done.
+func CrawlStream(ctx context.Context, startURL string, opts CrawlOptions) <-chan CrawlResult {
+	return defaultScraper.CrawlStream(ctx, startURL, opts)
+}
+
+// Crawls like Crawl, sending each page on the returned channel as soon as it
+// has been scraped. The crawl waits while the channel is full, so a slow
+// consumer holds it back rather than letting results pile up.
+func (s *Scraper) CrawlStream(ctx context.Context, startURL string, opts CrawlOptions) <-chan CrawlResult {
+	ch := make(chan CrawlResult)
+	go func() {
+		defer close(ch)
+		send := func(r CrawlResult) {
+			select {
+			case ch <- r:
+			case <-ctx.Done():
+			}
+		}
+
+		err := s.crawl(ctx, []string{startURL}, opts, nil, func(u string, p crawledPage) {
+			send(CrawlResult{URL: u, Text: p.text, Err: p.err})
+		})
+		if err != nil && ctx.Err() == nil {
+			send(CrawlResult{URL: startURL, Err: err})
+		}
+	}()
+	return ch
+}
+
+// Runs a breadth-first crawl from seeds. onLevel is called for each page
+// once its whole level is done, from the calling goroutine; onPage is called
+// as soon as the page is done, from the worker that fetched it. Either may
+// be nil. Running out of pages or time ends the crawl without an error.
+func (s *Scraper) crawl(ctx context.Context, seeds []string, opts CrawlOptions, onLevel, onPage func(string, crawledPage)) error {
+	budget := &crawlBudget{ctx: ctx, maxPages: int64(opts.MaxPages)}
+	if opts.MaxDuration > 0 {
+		var cancel context.CancelFunc
+		budget.ctx, cancel = context.WithTimeout(ctx, opts.MaxDuration)
+		defer cancel()
+	}
+
+	hosts := make(map[string]bool)
+	visited := make(map[string]bool)
+	var frontier []string
+	for _, seed := range seeds {
+		start, err := url.Parse(seed)
+		if err != nil {
+			return err
+		}
+		start.Fragment = ""
+
+		hosts[start.Host] = true
+		if !visited[start.String()] {
+			visited[start.String()] = true
+			frontier = append(frontier, start.String())
+		}
+	}
+
+	concurrency := max(opts.Concurrency, 1)
+
+	for depth := 0; len(frontier) > 0 && depth <= opts.MaxDepth && !budget.spent(); depth++ {
+		pages := s.crawlLevel(budget, frontier, concurrency, onPage)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		var next []string
+		for i, p := range pages {
+			if p.skipped {
+				continue
+			}
+			if onLevel != nil {
+				onLevel(frontier[i], p)
+			}
+			if p.err != nil {
+				continue
+			}
+
+			for _, link := range p.links {
+				if visited[link] {
+					continue
+				}
+				if opts.SameHost && !onHosts(hosts, link) {
+					continue
+				}
+				// Filtered links count as seen, so they're only matched once
+				visited[link] = true
+				if !opts.follows(link) {
+					continue
+				}
+				next = append(next, link)
+			}
+		}
+		frontier = next
+	}
+
+	return nil
+}
+
+type crawledPage struct {
+	text  string
+	links []string
+	err   error
+	// Not fetched, or cut off, because the crawl ran out of pages or time
+	skipped bool
+}
+
+// The page and time limits of a crawl, shared by its workers
+type crawlBudget struct {
+	// Done when the crawl's time is up
+	ctx      context.Context
+	maxPages int64
+	pages    atomic.Int64
+}
+
+// Claims one page of the budget, reporting false if none are left
+func (b *crawlBudget) take() bool {
+	if b.ctx.Err() != nil {
+		return false
+	}
+	return b.maxPages <= 0 || b.pages.Add(1) <= b.maxPages
+}
+
+// Reports whether the crawl has run out of pages or time
+func (b *crawlBudget) spent() bool {
+	return b.ctx.Err() != nil || (b.maxPages > 0 && b.pages.Load() >= b.maxPages)
+}
+
+// Fetches one BFS level, up to concurrency pages at a time, calling onPage
+// (if not nil) as each one finishes. Pages over the budget are skipped.
+func (s *Scraper) crawlLevel(budget *crawlBudget, urls []string, concurrency int, onPage func(string, crawledPage)) []crawledPage {
+	pages := make([]crawledPage, len(urls))
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+	for range min(concurrency, len(urls)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				if !budget.take() {
+					pages[i].skipped = true
+					continue
+				}
+				pages[i] = s.crawlPage(budget.ctx, urls[i])
+				if pages[i].err != nil && budget.ctx.Err() != nil {
+					pages[i] = crawledPage{skipped: true}
+					continue
+				}
+				if onPage != nil {
+					onPage(urls[i], pages[i])
+				}
+			}
+		}()
+	}
+
+	for i := range urls {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	return pages
+}
+
+func (s *Scraper) crawlPage(ctx context.Context, u string) crawledPage {
+	doc, pageURL, err := s.fetchDocument(ctx, u)
+	if err != nil {
+		return crawledPage{err: err}
+	}
+	return crawledPage{
+		text:  textFromNode(doc, s.extract),
+		links: linksFromHTML(doc, pageURL, 0, false, s.extract),
+	}
+}
+
+// Reports whether link passes the allow and deny patterns
+func (opts CrawlOptions) follows(link string) bool {
+	if opts.AllowPattern != nil && !opts.AllowPattern.MatchString(link) {
+		return false
+	}
+	return opts.DenyPattern == nil || !opts.DenyPattern.MatchString(link)
+}
+
+// Reports whether link points to one of hosts
+func onHosts(hosts map[string]bool, link string) bool {
+	u, err := url.Parse(link)
+	return err == nil && hosts[u.Host]
+}
+
+func sameHost(start *url.URL, link string) bool {
+	u, err := url.Parse(link)
+	return err == nil && u.Host == start.Host
+}
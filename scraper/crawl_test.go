@@ -0,0 +1,87 @@
+package scraper_test
+
+import (
+	"context"
+	"maps"
+	"regexp"
+	"slices"
+	"testing"
+
+	"github.com/charlescqian/go-scrape/scraper"
+	"github.com/charlescqian/go-scrape/scraper/scrapetest"
+)
+
+var crawlSite = scrapetest.Pages{
+	"https://example.com/": `<p>home</p><a href="/a">a</a><a href="/b#top">b</a>` +
+		`<a href="https://other.example/x">x</a><a href="/private/p">p</a>`,
+	"https://example.com/a":         `<p>page a</p><a href="/c">c</a><a href="/">home</a>`,
+	"https://example.com/b":         `<p>page b</p><a href="/a">a</a>`,
+	"https://example.com/c":         `<p>page c</p><a href="/d">d</a>`,
+	"https://example.com/d":         `<p>page d</p>`,
+	"https://example.com/private/p": `<p>private</p>`,
+	"https://other.example/x":       `<p>other</p>`,
+}
+
+func TestCrawl(t *testing.T) {
+	tests := []struct {
+		name  string
+		seeds []string
+		opts  scraper.CrawlOptions
+		want  []string
+	}{
+		{"start page only", nil, scraper.CrawlOptions{}, []string{"/"}},
+		{"one level", nil, scraper.CrawlOptions{MaxDepth: 1, SameHost: true}, []string{"/", "/a", "/b", "/private/p"}},
+		{"other hosts", nil, scraper.CrawlOptions{MaxDepth: 1}, []string{"/", "/a", "/b", "/private/p", "https://other.example/x"}},
+		{"two levels", nil, scraper.CrawlOptions{MaxDepth: 2, SameHost: true, Concurrency: 3}, []string{"/", "/a", "/b", "/c", "/private/p"}},
+		{"deny", nil, scraper.CrawlOptions{MaxDepth: 1, SameHost: true, DenyPattern: regexp.MustCompile(`/private/`)}, []string{"/", "/a", "/b"}},
+		{"allow", nil, scraper.CrawlOptions{MaxDepth: 3, AllowPattern: regexp.MustCompile(`/(a|c)$`)}, []string{"/", "/a", "/c"}},
+		{"seeds", []string{"https://example.com/c", "https://example.com/d"}, scraper.CrawlOptions{MaxDepth: 1}, []string{"/c", "/d"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := scraper.NewScraper(scraper.WithFetcher(crawlSite))
+			if err != nil {
+				t.Fatal(err)
+			}
+			seeds := tt.seeds
+			if seeds == nil {
+				seeds = []string{"https://example.com/"}
+			}
+			got, err := s.CrawlSeeds(context.Background(), seeds, tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var want []string
+			for _, u := range tt.want {
+				if u[0] == '/' {
+					u = "https://example.com" + u
+				}
+				want = append(want, u)
+			}
+			if urls := slices.Sorted(maps.Keys(got)); !slices.Equal(urls, want) {
+				t.Errorf("crawled %q, want %q", urls, want)
+			}
+			if text, ok := got["https://example.com/a"]; ok && text != "page a c home" {
+				t.Errorf("text of /a = %q, want %q", text, "page a c home")
+			}
+		})
+	}
+}
+
+func TestCrawlMaxPages(t *testing.T) {
+	s, err := scraper.NewScraper(scraper.WithFetcher(crawlSite))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := s.Crawl(context.Background(), "https://example.com/", scraper.CrawlOptions{MaxDepth: 3, MaxPages: 3, Concurrency: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 3 {
+		t.Errorf("crawled %d pages, want 3", len(got))
+	}
+	if _, ok := got["https://example.com/"]; !ok {
+		t.Error("start page missing")
+	}
+}
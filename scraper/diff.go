@@ -0,0 +1,55 @@
+package scraper
+
+import (
+	"errors"
+	"unicode/utf8"
+
+	"github.com/sergi/go-diff/diffmatchpatch"
+)
+
+// What happened to a DiffSegment's text between the old and new version
+type DiffOp string
+
+const (
+	DiffUnchanged DiffOp = "unchanged"
+	DiffAdded     DiffOp = "added"
+	DiffRemoved   DiffOp = "removed"
+)
+
+// A run of text in a diff
+type DiffSegment struct {
+	Op   DiffOp `json:"op"`
+	Text string `json:"text"`
+}
+
+// Compares two versions of a page's text, such as from successive scrapes
+// whose ContentHash differs, returning the segments that were kept, added
+// and removed, in reading order. Whitespace is normalized first, as by
+// HashText, so texts with the same hash diff as a single unchanged segment.
+// Fails if either text isn't valid UTF-8.
+func DiffText(oldText, newText string) ([]DiffSegment, error) {
+	if !utf8.ValidString(oldText) || !utf8.ValidString(newText) {
+		return nil, errors.New("text to diff is not valid UTF-8")
+	}
+
+	dmp := diffmatchpatch.New()
+	diffs := dmp.DiffMain(normalizeSpace(oldText), normalizeSpace(newText), false)
+	// Prefer changes along word boundaries over the shortest edit, which
+	// splits words up to reuse single letters
+	diffs = dmp.DiffCleanupSemantic(diffs)
+
+	segments := make([]DiffSegment, 0, len(diffs))
+	for _, d := range diffs {
+		var op DiffOp
+		switch d.Type {
+		case diffmatchpatch.DiffInsert:
+			op = DiffAdded
+		case diffmatchpatch.DiffDelete:
+			op = DiffRemoved
+		default:
+			op = DiffUnchanged
+		}
+		segments = append(segments, DiffSegment{Op: op, Text: d.Text})
+	}
+	return segments, nil
+}
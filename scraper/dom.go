@@ -0,0 +1,107 @@
+package scraper
+
+import (
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// Returns the value of the named attribute, or "" if n doesn't have it
+func getAttr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+// Reports whether n has the named attribute, even if it's empty
+func hasAttr(n *html.Node, key string) bool {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// Reports whether n is an element with the given tag name
+func isElement(n *html.Node, tag string) bool {
+	return n.Type == html.ElementNode && n.Data == tag
+}
+
+// Reports whether the space-separated attribute value contains token,
+// ignoring case (as used by rel)
+func hasToken(val, token string) bool {
+	for _, f := range strings.Fields(val) {
+		if strings.EqualFold(f, token) {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns the URL relative references in doc resolve against: the
+// <base href> if the page declares one, otherwise the page's own URL
+func baseURL(doc *html.Node, pageURL *url.URL) *url.URL {
+	var href string
+
+	var find func(*html.Node) bool
+	find = func(n *html.Node) bool {
+		if isElement(n, "base") {
+			if h := getAttr(n, "href"); h != "" {
+				href = h
+				return true
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if find(c) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if !find(doc) {
+		return pageURL
+	}
+	b, err := pageURL.Parse(strings.TrimSpace(href))
+	if err != nil {
+		return pageURL
+	}
+	return b
+}
+
+// Returns the first element with the given tag name in document order
+func findElement(n *html.Node, tag string) *html.Node {
+	if isElement(n, tag) {
+		return n
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if found := findElement(c, tag); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// Returns the raw text under n, without any whitespace handling
+func nodeText(n *html.Node) string {
+	var b strings.Builder
+
+	var traverse func(*html.Node)
+	traverse = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			b.WriteString(n.Data)
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			traverse(c)
+		}
+	}
+
+	traverse(n)
+
+	return b.String()
+}
@@ -0,0 +1,198 @@
+package scraper
+
+import (
+	"bufio"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/charset"
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+)
+
+// How much of the document is searched for a <meta> charset declaration,
+// matching the limit browsers use
+const charsetPrescanBytes = 1024
+
+// How much of a document without a declared charset is looked at to guess
+// its encoding
+const charsetSniffBytes = 4096
+
+// Rejects responses that declare a media type other than HTML. A missing
+// Content-Type is given the benefit of the doubt.
+func checkContentType(contentType string) error {
+	if contentType == "" {
+		return nil
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return fmt.Errorf("%w: %q", ErrUnsupportedContentType, contentType)
+	}
+	switch mediaType {
+	case "text/html", "application/xhtml+xml":
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrUnsupportedContentType, mediaType)
+}
+
+// Wraps resp.Body so it yields the decoded payload when the server sent a
+// Content-Encoding the transport didn't already undo for us
+func decodeBody(resp *http.Response) error {
+	enc := strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding")))
+
+	var r io.Reader
+	switch enc {
+	case "", "identity":
+		return nil
+	case "gzip", "x-gzip":
+		zr, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return err
+		}
+		r = zr
+	case "deflate":
+		// "deflate" is supposed to be zlib-wrapped, but plenty of servers
+		// send a raw deflate stream instead
+		br := bufio.NewReader(resp.Body)
+		if isZlibHeader(br) {
+			zr, err := zlib.NewReader(br)
+			if err != nil {
+				return err
+			}
+			r = zr
+		} else {
+			r = flate.NewReader(br)
+		}
+	default:
+		return fmt.Errorf("unsupported content encoding %q", enc)
+	}
+
+	resp.Body = &decodedBody{Reader: r, body: resp.Body}
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = -1
+	return nil
+}
+
+func isZlibHeader(br *bufio.Reader) bool {
+	b, err := br.Peek(2)
+	if err != nil {
+		return false
+	}
+	return b[0]&0x0f == 8 && (uint16(b[0])<<8|uint16(b[1]))%31 == 0
+}
+
+// A wrapping reader that closes both itself (if it can) and the underlying body
+type decodedBody struct {
+	io.Reader
+	body io.ReadCloser
+}
+
+func (d *decodedBody) Close() error {
+	if c, ok := d.Reader.(io.Closer); ok {
+		c.Close()
+	}
+	return d.body.Close()
+}
+
+// Wraps r so it yields UTF-8, transcoding from the charset declared in the
+// Content-Type header or, failing that, in a <meta> tag near the top of the
+// document. Without either, the encoding is guessed from the start of the
+// document, which is kept as UTF-8 if it's valid UTF-8. Unknown charsets
+// are read as UTF-8. Also returns a warning if the encoding is in doubt.
+func toUTF8(r io.Reader, contentType string) (io.Reader, *Warning) {
+	br := bufio.NewReaderSize(r, charsetSniffBytes)
+
+	var name string
+	if _, params, err := mime.ParseMediaType(contentType); err == nil {
+		name = params["charset"]
+	}
+	if name == "" {
+		prefix, _ := br.Peek(charsetPrescanBytes)
+		name = metaCharset(prefix)
+	}
+	var warning *Warning
+	if name == "" {
+		prefix, _ := br.Peek(charsetSniffBytes)
+		if validUTF8Prefix(prefix) {
+			return br, nil
+		}
+		var certain bool
+		_, name, certain = charset.DetermineEncoding(prefix, "")
+		if !certain {
+			warning = &Warning{
+				Code:    WarningGuessedCharset,
+				Message: fmt.Sprintf("no charset declared and not valid UTF-8, read as %s", name),
+			}
+		}
+	}
+
+	enc, canonical := charset.Lookup(name)
+	if enc == nil {
+		return br, &Warning{
+			Code:    WarningUnknownCharset,
+			Message: fmt.Sprintf("unknown charset %q, read as UTF-8", name),
+		}
+	}
+	if canonical == "utf-8" {
+		return br, warning
+	}
+	// A byte order mark outranks any declaration, and isn't part of the text
+	return transform.NewReader(br, unicode.BOMOverride(enc.NewDecoder())), warning
+}
+
+// Reports whether b is valid UTF-8, apart from a rune cut off at the end
+func validUTF8Prefix(b []byte) bool {
+	for i := len(b) - 1; i >= 0 && i >= len(b)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(b[i]) {
+			if !utf8.FullRune(b[i:]) {
+				b = b[:i]
+			}
+			break
+		}
+	}
+	return utf8.Valid(b)
+}
+
+// Finds the charset declared by <meta charset> or
+// <meta http-equiv="Content-Type"> in an HTML prefix
+func metaCharset(prefix []byte) string {
+	z := html.NewTokenizer(strings.NewReader(string(prefix)))
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			return ""
+		case html.StartTagToken, html.SelfClosingTagToken:
+			t := z.Token()
+			if t.Data != "meta" {
+				continue
+			}
+
+			var httpEquiv, content string
+			for _, a := range t.Attr {
+				switch a.Key {
+				case "charset":
+					return strings.TrimSpace(a.Val)
+				case "http-equiv":
+					httpEquiv = a.Val
+				case "content":
+					content = a.Val
+				}
+			}
+			if strings.EqualFold(httpEquiv, "content-type") {
+				if _, params, err := mime.ParseMediaType(content); err == nil && params["charset"] != "" {
+					return params["charset"]
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package scraper_test
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/zlib"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/charlescqian/go-scrape/scraper"
+)
+
+func deflated(t *testing.T, s string, wrapped bool) []byte {
+	t.Helper()
+	var b bytes.Buffer
+	if wrapped {
+		zw := zlib.NewWriter(&b)
+		zw.Write([]byte(s))
+		zw.Close()
+		return b.Bytes()
+	}
+	fw, err := flate.NewWriter(&b, flate.DefaultCompression)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fw.Write([]byte(s))
+	fw.Close()
+	return b.Bytes()
+}
+
+func TestContentEncodings(t *testing.T) {
+	const page = "<p>compressed page</p>"
+	tests := []struct {
+		name     string
+		encoding string
+		body     []byte
+		wantErr  bool
+	}{
+		{"identity", "", []byte(page), false},
+		{"gzip", "gzip", gzipped(t, page), false},
+		{"x-gzip", "x-gzip", gzipped(t, page), false},
+		{"zlib deflate", "deflate", deflated(t, page, true), false},
+		{"raw deflate", "deflate", deflated(t, page, false), false},
+		{"unknown", "compress", []byte(page), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "text/html")
+				if tt.encoding != "" {
+					w.Header().Set("Content-Encoding", tt.encoding)
+				}
+				w.Write(tt.body)
+			}))
+			defer srv.Close()
+
+			text, err := scraper.ExtractTextFromURL(srv.URL)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ExtractText() error = %v, want error %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && text != "compressed page" {
+				t.Errorf("ExtractText() = %q, want %q", text, "compressed page")
+			}
+		})
+	}
+}
@@ -0,0 +1,174 @@
+package scraper
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+)
+
+// Returned when robots.txt checking is enabled and the site's robots.txt
+// doesn't allow the Scraper's User-Agent to fetch the URL
+var ErrDisallowedByRobots = errors.New("disallowed by robots.txt")
+
+// Returned when a fetch is redirected more times than WithMaxRedirects allows
+var ErrTooManyRedirects = errors.New("too many redirects")
+
+// Returned when a redirect chain comes back to a URL it already visited,
+// with the same cookies, so following it would only go round again
+var ErrRedirectLoop = errors.New("redirect loop")
+
+// Returned when a redirect points anywhere but an http or https URL, such
+// as a file:// URL
+var ErrUnsafeRedirect = errors.New("redirect to unsupported scheme")
+
+// Returned when the response isn't HTML. The wrapping error names the
+// content type that was received.
+var ErrUnsupportedContentType = errors.New("unsupported content type")
+
+// Returned when a response body is larger than WithMaxBodySize allows
+var ErrBodyTooLarge = errors.New("response body too large")
+
+// Returned when a conditional request finds the page unchanged since the
+// ETag or time given to ContextWithConditional
+var ErrNotModified = errors.New("not modified")
+
+// Returned along with the text extracted so far when a document is nested
+// deeper or has more nodes than WithMaxDepth or WithMaxNodes allow
+var ErrTruncated = errors.New("document exceeds extraction limits, text truncated")
+
+// Returned along with the text of what was received when a response body
+// ends early, e.g. because the connection dropped. The wrapping error
+// names the read error.
+var ErrIncomplete = errors.New("response body ended early, content is incomplete")
+
+// Returned without sending a request when WithCircuitBreaker has stopped
+// requests to a host that kept failing. The wrapping error names the host.
+var ErrCircuitOpen = errors.New("circuit open, host is failing")
+
+// Returned along with the text when a page yields less text than
+// WithMinContentLength requires, such as a page rendered by JavaScript. The
+// wrapping error gives the length.
+var ErrInsufficientContent = errors.New("insufficient content")
+
+// Reports whether err still came with usable, if partial, text
+func isPartial(err error) bool {
+	return errors.Is(err, ErrTruncated) || errors.Is(err, ErrIncomplete)
+}
+
+// Returned when the target responds with a status other than 200 OK, or
+// other than those given to WithAcceptStatuses
+type FetchError struct {
+	URL        string
+	StatusCode int
+}
+
+func (e *FetchError) Error() string {
+	return fmt.Sprintf("failed to fetch page %s: %d %s", e.URL, e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+// Returned when a page redirects and WithFollowRedirects(false) is set
+type RedirectError struct {
+	URL        string
+	StatusCode int
+	// Where the redirect points, as sent by the server
+	Location string
+}
+
+func (e *RedirectError) Error() string {
+	return fmt.Sprintf("page %s redirects to %s: %d %s", e.URL, e.Location, e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+// Returned when the page was fetched but its body couldn't be read or parsed
+type ParseError struct {
+	URL string
+	Err error
+}
+
+func (e *ParseError) Error() string {
+	return "failed to parse page " + e.URL + ": " + e.Err.Error()
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// Kind of failure behind a NetworkError
+type NetworkErrorKind string
+
+const (
+	NetworkDNS        NetworkErrorKind = "dns"
+	NetworkConnection NetworkErrorKind = "connection"
+	NetworkTLS        NetworkErrorKind = "tls"
+	NetworkTimeout    NetworkErrorKind = "timeout"
+)
+
+// Returned when the target couldn't be reached: its name didn't resolve,
+// the connection failed or timed out, or the TLS handshake failed
+type NetworkError struct {
+	URL  string
+	Kind NetworkErrorKind
+	Err  error
+}
+
+func (e *NetworkError) Error() string {
+	return fmt.Sprintf("failed to fetch page %s: %s error: %v", e.URL, e.Kind, e.Err)
+}
+
+func (e *NetworkError) Unwrap() error {
+	return e.Err
+}
+
+// Wraps an error from sending a request to u in a NetworkError. Errors that
+// aren't about reaching the host, such as cancellation or redirect policy
+// violations, are returned unchanged.
+func networkError(u string, err error) error {
+	// The caller's context ending comes back as ctx.Err() itself. Transport
+	// timeouts also match context.DeadlineExceeded, but are network errors.
+	if err == context.Canceled || err == context.DeadlineExceeded ||
+		errors.Is(err, ErrTooManyRedirects) || errors.Is(err, ErrRedirectLoop) {
+		return err
+	}
+
+	// The client's *url.Error repeats the method and URL, so keep what it wraps
+	inner := err
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		inner = urlErr.Err
+	}
+
+	var dnsErr *net.DNSError
+	var netErr net.Error
+	var opErr *net.OpError
+	var kind NetworkErrorKind
+	switch {
+	case errors.As(err, &dnsErr):
+		kind = NetworkDNS
+	case isTLSError(err):
+		kind = NetworkTLS
+	case errors.As(err, &netErr) && netErr.Timeout():
+		kind = NetworkTimeout
+	case errors.As(err, &opErr), errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		kind = NetworkConnection
+	default:
+		return err
+	}
+	return &NetworkError{URL: u, Kind: kind, Err: inner}
+}
+
+func isTLSError(err error) bool {
+	var recordErr tls.RecordHeaderError
+	var alertErr tls.AlertError
+	var verifyErr *tls.CertificateVerificationError
+	var authorityErr x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	var invalidErr x509.CertificateInvalidError
+	return errors.As(err, &recordErr) || errors.As(err, &alertErr) ||
+		errors.As(err, &verifyErr) || errors.As(err, &authorityErr) ||
+		errors.As(err, &hostnameErr) || errors.As(err, &invalidErr)
+}
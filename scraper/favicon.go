@@ -0,0 +1,87 @@
+package scraper
+
+import (
+	"context"
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// Finds the URL of a page's icon: the largest one it declares with
+// <link rel="icon">, "shortcut icon" or "apple-touch-icon", or else the
+// site's /favicon.ico
+func ExtractFavicon(u string) (string, error) {
+	return defaultScraper.ExtractFavicon(context.Background(), u)
+}
+
+// Finds the URL of the icon of the page at u. The /favicon.ico fallback
+// isn't checked to exist.
+func (s *Scraper) ExtractFavicon(ctx context.Context, u string) (string, error) {
+	doc, pageURL, err := s.fetchDocument(ctx, u)
+	if err != nil {
+		return "", err
+	}
+	return faviconFromHTML(doc, pageURL), nil
+}
+
+func faviconFromHTML(doc *html.Node, pageURL *url.URL) string {
+	base := baseURL(doc, pageURL)
+	best, bestSize := "", -1
+
+	var traverse func(*html.Node)
+	traverse = func(n *html.Node) {
+		if isElement(n, "link") {
+			rel := getAttr(n, "rel")
+			touch := hasToken(rel, "apple-touch-icon") || hasToken(rel, "apple-touch-icon-precomposed")
+			if touch || hasToken(rel, "icon") {
+				if link, ok := resolveLink(base, getAttr(n, "href")); ok {
+					if size := iconSize(getAttr(n, "sizes"), touch); size > bestSize {
+						best, bestSize = link, size
+					}
+				}
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			traverse(c)
+		}
+	}
+
+	traverse(doc)
+
+	if best == "" {
+		// Browsers look here when a page doesn't declare an icon
+		best, _ = resolveLink(pageURL, "/favicon.ico")
+	}
+	return best
+}
+
+// Returns the largest size in a sizes attribute such as "16x16 32x32", with
+// "any" (a scalable icon) beating everything. Without a hint, Apple touch
+// icons are taken to be 180 pixels, the size iOS asks for, and others 0.
+func iconSize(sizes string, touch bool) int {
+	size := -1
+	for _, f := range strings.Fields(strings.ToLower(sizes)) {
+		if f == "any" {
+			return math.MaxInt
+		}
+		w, h, ok := strings.Cut(f, "x")
+		if !ok {
+			continue
+		}
+		width, errW := strconv.Atoi(w)
+		height, errH := strconv.Atoi(h)
+		if errW == nil && errH == nil {
+			size = max(size, min(width, height))
+		}
+	}
+	if size >= 0 {
+		return size
+	}
+	if touch {
+		return 180
+	}
+	return 0
+}
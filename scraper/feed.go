@@ -0,0 +1,249 @@
+package scraper
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"io"
+	"mime"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/charset"
+)
+
+// Returned by DetectAndParseFeed when the URL is neither a feed nor a page
+// linking to one
+var ErrNotFeed = errors.New("not an RSS or Atom feed")
+
+// An entry in an RSS or Atom feed
+type FeedItem struct {
+	Title string `json:"title"`
+	Link  string `json:"link"`
+	// Zero if the item has no date that could be parsed
+	Published time.Time `json:"published"`
+	// Plain text of the item's description or summary
+	Summary string `json:"summary"`
+}
+
+// RSS 2.0 has its items in a <channel>, RSS 1.0 (RDF) beside it
+type rssFeed struct {
+	ChannelItems []rssItem `xml:"channel>item"`
+	Items        []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string `xml:"title"`
+	Link        string `xml:"link"`
+	PubDate     string `xml:"pubDate"`
+	Date        string `xml:"http://purl.org/dc/elements/1.1/ date"`
+	Description string `xml:"description"`
+}
+
+type atomFeed struct {
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomEntry struct {
+	Title string `xml:"title"`
+	Links []struct {
+		Href string `xml:"href,attr"`
+		Rel  string `xml:"rel,attr"`
+	} `xml:"link"`
+	Published string `xml:"published"`
+	Updated   string `xml:"updated"`
+	Summary   string `xml:"summary"`
+	Content   string `xml:"content"`
+}
+
+// Fetches the RSS or Atom feed at u and returns its items. If u is an HTML
+// page instead, the feed it advertises with <link rel="alternate"> is used.
+func DetectAndParseFeed(u string) ([]FeedItem, error) {
+	return defaultScraper.DetectAndParseFeed(context.Background(), u)
+}
+
+// Fetches the RSS or Atom feed at u, or the one the HTML page at u links to,
+// and returns its items
+func (s *Scraper) DetectAndParseFeed(ctx context.Context, u string) ([]FeedItem, error) {
+	items, feedURL, err := s.fetchFeed(ctx, u)
+	if err != nil || feedURL == "" {
+		return items, err
+	}
+
+	// Only follow one hop, so pages can't send us round in circles
+	items, feedURL, err = s.fetchFeed(ctx, feedURL)
+	if err == nil && feedURL != "" {
+		return nil, ErrNotFeed
+	}
+	return items, err
+}
+
+// Fetches u and parses it as a feed if it is one. For an HTML page, returns
+// the URL of the feed it links to instead.
+func (s *Scraper) fetchFeed(ctx context.Context, u string) ([]FeedItem, string, error) {
+	resp, err := s.get(ctx, u)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+
+	contentType := resp.Header.Get("Content-Type")
+	pageURL := resp.Request.URL
+	if isHTMLType(contentType) {
+		body, warning := toUTF8(resp.Body, contentType)
+		addWarning(ctx, warning)
+		doc, err := html.Parse(body)
+		if ctx.Err() != nil {
+			return nil, "", ctx.Err()
+		}
+		if err != nil {
+			return nil, "", &ParseError{URL: pageURL.String(), Err: err}
+		}
+		feedURL, ok := feedLink(doc, pageURL)
+		if !ok {
+			return nil, "", ErrNotFeed
+		}
+		return nil, feedURL, nil
+	}
+
+	items, err := parseFeed(resp.Body, pageURL)
+	if ctx.Err() != nil {
+		return nil, "", ctx.Err()
+	}
+	if err != nil && !errors.Is(err, ErrNotFeed) {
+		return nil, "", &ParseError{URL: pageURL.String(), Err: err}
+	}
+	return items, "", err
+}
+
+func isHTMLType(contentType string) bool {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	return mediaType == "text/html" || mediaType == "application/xhtml+xml"
+}
+
+// Finds the feed a page advertises in its <head>
+func feedLink(doc *html.Node, pageURL *url.URL) (string, bool) {
+	base := baseURL(doc, pageURL)
+
+	var link string
+	var traverse func(*html.Node)
+	traverse = func(n *html.Node) {
+		if link != "" {
+			return
+		}
+		if isElement(n, "link") && hasToken(getAttr(n, "rel"), "alternate") {
+			switch strings.ToLower(strings.TrimSpace(getAttr(n, "type"))) {
+			case "application/rss+xml", "application/atom+xml":
+				if l, ok := resolveLink(base, getAttr(n, "href")); ok {
+					link = l
+					return
+				}
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			traverse(c)
+		}
+	}
+	traverse(doc)
+
+	return link, link != ""
+}
+
+// Decodes an RSS or Atom document, telling them apart by the root element
+func parseFeed(r io.Reader, feedURL *url.URL) ([]FeedItem, error) {
+	dec := xml.NewDecoder(r)
+	dec.CharsetReader = charset.NewReaderLabel
+
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return nil, ErrNotFeed
+		}
+		if err != nil {
+			return nil, err
+		}
+		root, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		switch root.Name.Local {
+		case "rss", "RDF":
+			var f rssFeed
+			if err := dec.DecodeElement(&f, &root); err != nil {
+				return nil, err
+			}
+			return rssItems(append(f.ChannelItems, f.Items...), feedURL), nil
+		case "feed":
+			var f atomFeed
+			if err := dec.DecodeElement(&f, &root); err != nil {
+				return nil, err
+			}
+			return atomItems(f.Entries, feedURL), nil
+		}
+		return nil, ErrNotFeed
+	}
+}
+
+func rssItems(entries []rssItem, feedURL *url.URL) []FeedItem {
+	items := make([]FeedItem, 0, len(entries))
+	for _, e := range entries {
+		item := FeedItem{
+			Title:   strings.TrimSpace(e.Title),
+			Link:    resolveFeedLink(feedURL, e.Link),
+			Summary: htmlToText(e.Description),
+		}
+		for _, d := range []string{e.PubDate, e.Date} {
+			if t, ok := parseDate(d); ok {
+				item.Published = t
+				break
+			}
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+func atomItems(entries []atomEntry, feedURL *url.URL) []FeedItem {
+	items := make([]FeedItem, 0, len(entries))
+	for _, e := range entries {
+		item := FeedItem{Title: strings.TrimSpace(e.Title)}
+
+		// The rel="alternate" link, which is also what a missing rel means
+		for _, l := range e.Links {
+			if l.Rel == "" || l.Rel == "alternate" {
+				item.Link = resolveFeedLink(feedURL, l.Href)
+				break
+			}
+		}
+
+		for _, d := range []string{e.Published, e.Updated} {
+			if t, ok := parseDate(d); ok {
+				item.Published = t
+				break
+			}
+		}
+
+		item.Summary = htmlToText(e.Summary)
+		if item.Summary == "" {
+			item.Summary = htmlToText(e.Content)
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+func resolveFeedLink(feedURL *url.URL, href string) string {
+	if link, ok := resolveLink(feedURL, href); ok {
+		return link
+	}
+	return strings.TrimSpace(href)
+}
+
+// Feed descriptions usually hold escaped HTML, so reduce them to their text
+func htmlToText(s string) string {
+	text, _ := extractTextFromReader(strings.NewReader(s), defaultExtractOptions)
+	return text
+}
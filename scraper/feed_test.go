@@ -0,0 +1,91 @@
+package scraper_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/charlescqian/go-scrape/scraper"
+)
+
+const (
+	testRSS = `<?xml version="1.0"?>
+<rss version="2.0"><channel><title>Blog</title>
+<item><title> First </title><link>/posts/1</link><pubDate>Mon, 02 Jan 2006 15:04:05 +0000</pubDate>
+<description>&lt;p&gt;Hello &lt;b&gt;world&lt;/b&gt;&lt;/p&gt;</description></item>
+<item><title>Undated</title><link>https://other.example/2</link></item>
+</channel></rss>`
+	testRDF = `<?xml version="1.0"?>
+<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#" xmlns="http://purl.org/rss/1.0/" xmlns:dc="http://purl.org/dc/elements/1.1/">
+<channel><title>Blog</title></channel>
+<item><title>First</title><link>/posts/1</link><dc:date>2006-01-02T15:04:05Z</dc:date><description>Hello world</description></item>
+</rdf:RDF>`
+	testAtom = `<?xml version="1.0" encoding="utf-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom"><title>Blog</title>
+<entry><title>First</title><link rel="self" href="/self"/><link href="/posts/1"/>
+<updated>2006-01-02T15:04:05Z</updated><content type="html">&lt;p&gt;Hello world&lt;/p&gt;</content></entry>
+</feed>`
+)
+
+func TestDetectAndParseFeed(t *testing.T) {
+	type page struct{ contentType, body string }
+	pages := map[string]page{
+		"/rss":       {"application/rss+xml", testRSS},
+		"/rdf":       {"application/rdf+xml", testRDF},
+		"/atom":      {"application/atom+xml", testAtom},
+		"/blog":      {"text/html", `<html><head><link rel="alternate" type="application/atom+xml" href="/atom"></head></html>`},
+		"/plain":     {"text/html", `<p>no feed here</p>`},
+		"/xml":       {"application/xml", `<note>not a feed</note>`},
+		"/to-html":   {"text/html", `<link rel="alternate" type="application/rss+xml" href="/plain-rss">`},
+		"/plain-rss": {"text/html", `<link rel="alternate" type="application/rss+xml" href="/to-html">`},
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p, ok := pages[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", p.contentType)
+		w.Write([]byte(p.body))
+	}))
+	defer srv.Close()
+
+	s, err := scraper.NewScraper()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	published := time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC)
+	first := scraper.FeedItem{Title: "First", Link: srv.URL + "/posts/1", Published: published, Summary: "Hello world"}
+	tests := []struct {
+		path    string
+		want    []scraper.FeedItem
+		wantErr error
+	}{
+		{"/rss", []scraper.FeedItem{first, {Title: "Undated", Link: "https://other.example/2"}}, nil},
+		{"/rdf", []scraper.FeedItem{first}, nil},
+		{"/atom", []scraper.FeedItem{first}, nil},
+		{"/blog", []scraper.FeedItem{first}, nil},
+		{"/plain", nil, scraper.ErrNotFeed},
+		{"/xml", nil, scraper.ErrNotFeed},
+		{"/to-html", nil, scraper.ErrNotFeed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, err := s.DetectAndParseFeed(context.Background(), srv.URL+tt.path)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("DetectAndParseFeed() error = %v, want %v", err, tt.wantErr)
+			}
+			for i := range got {
+				got[i].Published = got[i].Published.UTC()
+			}
+			if tt.want != nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DetectAndParseFeed() = %+v\nwant %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package scraper
+
+import (
+	"context"
+	"io"
+	"net/http"
+)
+
+// Retrieves the HTML of a page. Implement this to plug in another way of
+// loading pages, such as a headless browser for sites that render their
+// content with JavaScript. The returned HTML must be UTF-8 encoded. Fetch
+// may be called from several goroutines at once, like the Scraper using it.
+//
+// A Scraper is itself a Fetcher that uses plain HTTP.
+type Fetcher interface {
+	Fetch(ctx context.Context, u string) (io.ReadCloser, error)
+}
+
+// Loads pages through f instead of over HTTP. The robots.txt, rate limit
+// and body size settings still apply, but retries, redirects and other
+// HTTP options are up to f. Sitemaps and CheckURL keep using HTTP.
+func WithFetcher(f Fetcher) Option {
+	return func(s *Scraper) {
+		s.fetcher = f
+	}
+}
+
+// Fetches the page at u over HTTP, returning its body decoded to UTF-8.
+// The caller must close it.
+func (s *Scraper) Fetch(ctx context.Context, u string) (io.ReadCloser, error) {
+	resp, err := s.fetchHTTP(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// Fetches u through the configured Fetcher, dressing the result up as a
+// response so callers needn't care where it came from
+func (s *Scraper) fetchWithFetcher(ctx context.Context, u string) (*http.Response, error) {
+	req, err := s.newRequest(ctx, http.MethodGet, u)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.robots != nil {
+		if err := s.checkRobots(req); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.throttle(ctx, req.URL.Host); err != nil {
+		return nil, err
+	}
+	if s.breakers != nil {
+		if err := s.breakers.allow(req.URL.Host); err != nil {
+			return nil, err
+		}
+	}
+
+	body, err := s.fetcher.Fetch(ctx, req.URL.String())
+	if s.breakers != nil {
+		if ctx.Err() != nil {
+			s.breakers.release(req.URL.Host)
+		} else {
+			s.breakers.record(req.URL.Host, err != nil)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if rec := statsFrom(ctx); rec != nil {
+		body = &countingBody{ReadCloser: body, rec: rec}
+	}
+
+	resp := &http.Response{
+		StatusCode:    http.StatusOK,
+		Header:        http.Header{"Content-Type": {"text/html; charset=utf-8"}},
+		Body:          body,
+		ContentLength: -1,
+		Request:       req,
+	}
+	if s.maxBodySize > 0 {
+		resp.Body = &decodedBody{
+			Reader: &limitedReader{r: body, n: s.maxBodySize},
+			body:   body,
+		}
+	}
+	return resp, nil
+}
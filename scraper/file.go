@@ -0,0 +1,46 @@
+package scraper
+
+import (
+	"net/url"
+	"os"
+	"path/filepath"
+)
+
+// Lets the Scraper read file:// URLs and bare file paths from the local
+// disk, e.g. for working offline against saved pages. Off by default, as it
+// would let anyone who controls the URLs read the machine's files. Pages
+// fetched over HTTP still can't redirect to a file.
+func WithLocalFiles(enabled bool) Option {
+	return func(s *Scraper) {
+		s.localFiles = enabled
+	}
+}
+
+// Turns a bare path into a file:// URL. Anything with a scheme or host is
+// returned unchanged.
+func localFileURL(u string) string {
+	parsed, err := url.Parse(u)
+	if err == nil && (parsed.Scheme != "" || parsed.Host != "") {
+		return u
+	}
+
+	abs, err := filepath.Abs(u)
+	if err != nil {
+		return u
+	}
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(abs)}).String()
+}
+
+// Extracts all visible text from the HTML file at path, decoded from the
+// charset it declares. The package-level functions taking a URL don't read
+// local files; use a Scraper created with WithLocalFiles for those.
+func ExtractTextFromFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	r, _ := toUTF8(f, "")
+	return extractTextFromReader(r, defaultExtractOptions)
+}
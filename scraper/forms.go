@@ -0,0 +1,177 @@
+package scraper
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// A <form> on a page
+type Form struct {
+	// Absolute URL the form submits to
+	Action string `json:"action"`
+	// Upper-case HTTP method, GET if the form doesn't say
+	Method string      `json:"method"`
+	Fields []FormField `json:"fields"`
+}
+
+// An <input>, <select> or <textarea> in a form
+type FormField struct {
+	Name string `json:"name"`
+	// The input's type, or "select" or "textarea" for those elements
+	Type string `json:"type"`
+	// The value the field is submitted with. Checkboxes and radio buttons
+	// are only submitted if Checked.
+	Value string `json:"value"`
+	// Whether a checkbox or radio button is checked as the page stands
+	Checked bool `json:"checked,omitempty"`
+}
+
+// Extracts every form on a page with its fields
+func ExtractForms(u string) ([]Form, error) {
+	return defaultScraper.ExtractForms(context.Background(), u)
+}
+
+// Extracts every form on the page at u with its fields
+func (s *Scraper) ExtractForms(ctx context.Context, u string) ([]Form, error) {
+	doc, pageURL, err := s.fetchDocument(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+	return formsFromHTML(doc, pageURL), nil
+}
+
+func formsFromHTML(doc *html.Node, pageURL *url.URL) []Form {
+	base := baseURL(doc, pageURL)
+	var forms []Form
+
+	var traverse func(*html.Node)
+	traverse = func(n *html.Node) {
+		if isElement(n, "form") {
+			forms = append(forms, formFromNode(n, base, pageURL))
+			return
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			traverse(c)
+		}
+	}
+
+	traverse(doc)
+
+	return forms
+}
+
+func formFromNode(n *html.Node, base, pageURL *url.URL) Form {
+	form := Form{
+		Action: formAction(getAttr(n, "action"), base, pageURL),
+		Method: strings.ToUpper(strings.TrimSpace(getAttr(n, "method"))),
+		Fields: []FormField{},
+	}
+	if form.Method == "" {
+		form.Method = "GET"
+	}
+
+	var traverse func(*html.Node)
+	traverse = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			switch n.Data {
+			case "input":
+				form.Fields = append(form.Fields, inputField(n))
+				return
+			case "select":
+				form.Fields = append(form.Fields, FormField{
+					Name:  getAttr(n, "name"),
+					Type:  "select",
+					Value: selectedOption(n),
+				})
+				return
+			case "textarea":
+				form.Fields = append(form.Fields, FormField{
+					Name:  getAttr(n, "name"),
+					Type:  "textarea",
+					Value: nodeText(n),
+				})
+				return
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			traverse(c)
+		}
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		traverse(c)
+	}
+	return form
+}
+
+// Resolves a form's action. A form without one submits back to the page
+// it's on.
+func formAction(action string, base, pageURL *url.URL) string {
+	action = strings.TrimSpace(action)
+	if action == "" {
+		return pageURL.String()
+	}
+	ref, err := url.Parse(action)
+	if err != nil {
+		return pageURL.String()
+	}
+	return base.ResolveReference(ref).String()
+}
+
+func inputField(n *html.Node) FormField {
+	field := FormField{
+		Name:  getAttr(n, "name"),
+		Type:  strings.ToLower(strings.TrimSpace(getAttr(n, "type"))),
+		Value: getAttr(n, "value"),
+	}
+	if field.Type == "" {
+		field.Type = "text"
+	}
+
+	if field.Type == "checkbox" || field.Type == "radio" {
+		field.Checked = hasAttr(n, "checked")
+		// Browsers submit checked boxes without a value as "on"
+		if !hasAttr(n, "value") {
+			field.Value = "on"
+		}
+	}
+	return field
+}
+
+// Returns the value of the selected option in a <select>, or of the first
+// option if none is marked selected
+func selectedOption(n *html.Node) string {
+	var first, selected *html.Node
+
+	var find func(*html.Node)
+	find = func(n *html.Node) {
+		if isElement(n, "option") {
+			if first == nil {
+				first = n
+			}
+			if selected == nil && hasAttr(n, "selected") {
+				selected = n
+			}
+			return
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			find(c)
+		}
+	}
+
+	find(n)
+
+	if selected == nil {
+		selected = first
+	}
+	if selected == nil {
+		return ""
+	}
+	if hasAttr(selected, "value") {
+		return getAttr(selected, "value")
+	}
+	return normalizeSpace(nodeText(selected))
+}
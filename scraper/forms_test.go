@@ -0,0 +1,49 @@
+package scraper_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/charlescqian/go-scrape/scraper"
+	"github.com/charlescqian/go-scrape/scraper/scrapetest"
+)
+
+func TestExtractForms(t *testing.T) {
+	const page = `<form action="/search" method="post">
+		<input name="q" value="go">
+		<input type="checkbox" name="exact" checked>
+		<input type="checkbox" name="safe" value="strict">
+		<input type="radio" name="sort" value="new">
+		<input type="radio" name="sort" value="top" checked>
+		<select name="lang"><option value="en">English</option><option selected>Deutsch</option></select>
+		<textarea name="note">hi</textarea>
+	</form>`
+	s, err := scraper.NewScraper(scraper.WithFetcher(scrapetest.Pages{"https://example.com/a/": page}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	forms, err := s.ExtractForms(context.Background(), "https://example.com/a/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(forms) != 1 {
+		t.Fatalf("got %d forms, want 1", len(forms))
+	}
+	form := forms[0]
+	if form.Action != "https://example.com/search" || form.Method != "POST" {
+		t.Errorf("form submits with %s %s, want POST https://example.com/search", form.Method, form.Action)
+	}
+	want := []scraper.FormField{
+		{Name: "q", Type: "text", Value: "go"},
+		{Name: "exact", Type: "checkbox", Value: "on", Checked: true},
+		{Name: "safe", Type: "checkbox", Value: "strict"},
+		{Name: "sort", Type: "radio", Value: "new"},
+		{Name: "sort", Type: "radio", Value: "top", Checked: true},
+		{Name: "lang", Type: "select", Value: "Deutsch"},
+		{Name: "note", Type: "textarea", Value: "hi"},
+	}
+	if !slices.Equal(form.Fields, want) {
+		t.Errorf("Fields = %+v\nwant %+v", form.Fields, want)
+	}
+}
@@ -0,0 +1,29 @@
+package scraper
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// Returns a fingerprint of the visible text of a page, for telling whether
+// it changed since it was last fetched
+func ContentHash(u string) (string, error) {
+	return defaultScraper.ContentHash(context.Background(), u)
+}
+
+// Returns a fingerprint of the visible text of the page at u
+func (s *Scraper) ContentHash(ctx context.Context, u string) (string, error) {
+	text, err := s.ExtractTextContext(ctx, u)
+	if err != nil {
+		return "", err
+	}
+	return HashText(text), nil
+}
+
+// Returns the hex SHA-256 of text with its whitespace normalized, so text
+// differing only in spacing or line breaks hashes the same
+func HashText(text string) string {
+	sum := sha256.Sum256([]byte(normalizeSpace(text)))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,113 @@
+package scraper
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// An <img> on a page. Width and Height are 0 when not given as attributes.
+type Image struct {
+	Src    string   `json:"src"`
+	Alt    string   `json:"alt"`
+	Width  int      `json:"width,omitempty"`
+	Height int      `json:"height,omitempty"`
+	Srcset []string `json:"srcset,omitempty"`
+}
+
+// Extracts every image on a page, with sources resolved to absolute URLs
+func ExtractImages(u string) ([]Image, error) {
+	return defaultScraper.ExtractImages(context.Background(), u)
+}
+
+// Extracts every image on the page at u
+func (s *Scraper) ExtractImages(ctx context.Context, u string) ([]Image, error) {
+	doc, pageURL, err := s.fetchDocument(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+	return imagesFromHTML(doc, pageURL, s.extract), nil
+}
+
+func imagesFromHTML(doc *html.Node, pageURL *url.URL, o *extractOptions) []Image {
+	return imagesUnder(doc, baseURL(doc, pageURL), o.maxResults, o)
+}
+
+// Collects the images within n in document order, resolving their sources
+// against base, and stopping once it has limit of them if limit is above 0.
+// Only o's depth and node limits apply.
+func imagesUnder(n *html.Node, base *url.URL, limit int, o *extractOptions) []Image {
+	var images []Image
+
+	lim := o.newWalkLimits()
+	var traverse func(*html.Node)
+	traverse = func(n *html.Node) {
+		if !lim.enter() {
+			return
+		}
+		defer lim.leave()
+		if limit > 0 && len(images) >= limit {
+			return
+		}
+		if isElement(n, "img") {
+			if img, ok := imageFromNode(n, base); ok {
+				images = append(images, img)
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			traverse(c)
+		}
+	}
+
+	traverse(n)
+
+	return images
+}
+
+func imageFromNode(n *html.Node, base *url.URL) (Image, bool) {
+	img := Image{
+		Alt:    strings.TrimSpace(getAttr(n, "alt")),
+		Width:  parseDimension(getAttr(n, "width")),
+		Height: parseDimension(getAttr(n, "height")),
+		Srcset: parseSrcset(base, getAttr(n, "srcset")),
+	}
+
+	if src, ok := resolveLink(base, getAttr(n, "src")); ok {
+		img.Src = src
+	} else if len(img.Srcset) > 0 {
+		// Responsive images sometimes only declare srcset
+		img.Src = img.Srcset[0]
+	} else {
+		return Image{}, false
+	}
+	return img, true
+}
+
+// Returns the absolute URLs of the candidates in a srcset attribute, e.g.
+// "a.jpg 1x, b.jpg 2x"
+func parseSrcset(base *url.URL, srcset string) []string {
+	var urls []string
+	for _, candidate := range strings.Split(srcset, ",") {
+		fields := strings.Fields(candidate)
+		if len(fields) == 0 {
+			continue
+		}
+		if u, ok := resolveLink(base, fields[0]); ok {
+			urls = append(urls, u)
+		}
+	}
+	return urls
+}
+
+// Parses a width or height attribute, tolerating a "px" suffix
+func parseDimension(v string) int {
+	v = strings.TrimSuffix(strings.TrimSpace(v), "px")
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 {
+		return 0
+	}
+	return n
+}
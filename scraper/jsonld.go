@@ -0,0 +1,73 @@
+package scraper
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// Extracts the schema.org objects embedded in a page's
+// <script type="application/ld+json"> blocks. Blocks that aren't valid JSON
+// are skipped.
+func ExtractJSONLD(u string) ([]map[string]interface{}, error) {
+	return defaultScraper.ExtractJSONLD(context.Background(), u)
+}
+
+// Extracts the JSON-LD objects embedded in the page at u
+func (s *Scraper) ExtractJSONLD(ctx context.Context, u string) ([]map[string]interface{}, error) {
+	doc, _, err := s.fetchDocument(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+	return jsonLDFromHTML(doc), nil
+}
+
+func jsonLDFromHTML(doc *html.Node) []map[string]interface{} {
+	var objects []map[string]interface{}
+
+	var traverse func(*html.Node)
+	traverse = func(n *html.Node) {
+		if isElement(n, "script") && isJSONLD(getAttr(n, "type")) {
+			if n.FirstChild != nil {
+				objects = append(objects, parseJSONLD(n.FirstChild.Data)...)
+			}
+			return
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			traverse(c)
+		}
+	}
+
+	traverse(doc)
+
+	return objects
+}
+
+func isJSONLD(scriptType string) bool {
+	mediaType, _, _ := strings.Cut(scriptType, ";")
+	return strings.EqualFold(strings.TrimSpace(mediaType), "application/ld+json")
+}
+
+// Decodes one JSON-LD block, which may hold a single object or an array
+func parseJSONLD(data string) []map[string]interface{} {
+	var v interface{}
+	if err := json.Unmarshal([]byte(strings.TrimSpace(data)), &v); err != nil {
+		return nil
+	}
+
+	switch v := v.(type) {
+	case map[string]interface{}:
+		return []map[string]interface{}{v}
+	case []interface{}:
+		var objects []map[string]interface{}
+		for _, item := range v {
+			if obj, ok := item.(map[string]interface{}); ok {
+				objects = append(objects, obj)
+			}
+		}
+		return objects
+	}
+	return nil
+}
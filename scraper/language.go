@@ -0,0 +1,60 @@
+package scraper
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/abadojack/whatlanggo"
+	"golang.org/x/net/html"
+)
+
+// Returned by DetectLanguage when the language can't be determined
+const UnknownLanguage = "unknown"
+
+// Below this many characters statistical detection is mostly guesswork
+const minDetectChars = 20
+
+// Guesses the language of text, returning an ISO 639-1 code such as "en".
+// Text that is too short or too ambiguous gives UnknownLanguage.
+func DetectLanguage(text string) (string, error) {
+	text = strings.TrimSpace(text)
+	if utf8.RuneCountInString(text) < minDetectChars {
+		return UnknownLanguage, nil
+	}
+
+	info := whatlanggo.Detect(text)
+	code := info.Lang.Iso6391()
+	if code == "" || !info.IsReliable() {
+		return UnknownLanguage, nil
+	}
+	return code, nil
+}
+
+// Works out a page's language, preferring the <html lang> attribute and
+// falling back to detection on its visible text
+func pageLanguage(doc *html.Node, o *extractOptions) string {
+	if lang := primaryLanguage(htmlLang(doc)); lang != "" {
+		return lang
+	}
+
+	lang, _ := DetectLanguage(textFromNode(doc, o))
+	return lang
+}
+
+// Returns the trimmed lang attribute of the root <html> element, or "" if
+// it has none
+func htmlLang(doc *html.Node) string {
+	if root := findElement(doc, "html"); root != nil {
+		return strings.TrimSpace(getAttr(root, "lang"))
+	}
+	return ""
+}
+
+// Reduces a language tag like "en-US" to its primary subtag
+func primaryLanguage(tag string) string {
+	tag = strings.TrimSpace(tag)
+	if i := strings.IndexAny(tag, "-_"); i >= 0 {
+		tag = tag[:i]
+	}
+	return strings.ToLower(tag)
+}
@@ -0,0 +1,90 @@
+package scraper
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"unicode/utf8"
+)
+
+const defaultMaxBodySize = 10 << 20
+
+// Limits how many bytes of (decoded) response body are read per fetch.
+// Larger bodies fail with ErrBodyTooLarge. Zero or less disables the limit.
+func WithMaxBodySize(n int64) Option {
+	return func(s *Scraper) {
+		s.maxBodySize = n
+	}
+}
+
+// Makes ExtractText, ExtractTextWithURL, ExtractTextWithWarnings and
+// ExtractWithStats fail with ErrInsufficientContent when a page yields fewer
+// than n characters of text, as pages rendered by JavaScript do. Zero, the
+// default, disables the check.
+func WithMinContentLength(n int) Option {
+	return func(s *Scraper) {
+		s.minContent = n
+	}
+}
+
+// Returns ErrInsufficientContent if text is shorter than WithMinContentLength
+// allows
+func (s *Scraper) checkContentLength(text string) error {
+	if s.minContent <= 0 {
+		return nil
+	}
+	if n := utf8.RuneCountInString(text); n < s.minContent {
+		return fmt.Errorf("%w: %d characters, want at least %d", ErrInsufficientContent, n, s.minContent)
+	}
+	return nil
+}
+
+// Ends the stream cleanly at the first read error other than the body
+// limit, keeping the error so the content read so far can still be used.
+// Exceeding the body limit stays fatal, as it's a policy, not a glitch.
+type partialReader struct {
+	r   io.Reader
+	err error
+}
+
+func (p *partialReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	if err != nil && err != io.EOF && !errors.Is(err, ErrBodyTooLarge) {
+		p.err = err
+		return n, io.EOF
+	}
+	return n, err
+}
+
+// Returns ErrIncomplete wrapping the read error, if there was one
+func (p *partialReader) incomplete() error {
+	if p.err == nil {
+		return nil
+	}
+	return fmt.Errorf("%w: %w", ErrIncomplete, p.err)
+}
+
+// Reads at most n bytes from r, failing with ErrBodyTooLarge if r has more
+type limitedReader struct {
+	r io.Reader
+	n int64
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if l.n <= 0 {
+		// Only an actual extra byte means the limit was exceeded
+		var b [1]byte
+		n, err := l.r.Read(b[:])
+		if n > 0 {
+			return 0, ErrBodyTooLarge
+		}
+		return 0, err
+	}
+
+	if int64(len(p)) > l.n {
+		p = p[:l.n]
+	}
+	n, err := l.r.Read(p)
+	l.n -= int64(n)
+	return n, err
+}
@@ -0,0 +1,59 @@
+package scraper_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/charlescqian/go-scrape/scraper"
+	"github.com/charlescqian/go-scrape/scraper/scrapetest"
+)
+
+// Content nested past the depth limit is left out by every extractor that
+// walks the page, not just text extraction
+func TestMaxDepthBoundsExtractors(t *testing.T) {
+	const depth = 50
+	deep := strings.Repeat("<div>", depth) +
+		`<h2>Deep</h2><a href="/deep">deep</a><img src="/deep.png"><table><tr><td>deep</td></tr></table>` +
+		strings.Repeat("</div>", depth)
+	page := `<h1>Top</h1><p><a href="/top">top</a><img src="/top.png"></p><table><tr><td>top</td></tr></table>` + deep
+
+	s, err := scraper.NewScraper(
+		scraper.WithFetcher(scrapetest.Pages{"https://example.com/": page}),
+		scraper.WithMaxDepth(depth/2),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	const u = "https://example.com/"
+
+	tests := []struct {
+		name    string
+		extract func() (any, error)
+	}{
+		{"links", func() (any, error) { return s.ExtractLinks(ctx, u) }},
+		{"links with text", func() (any, error) { return s.ExtractLinksWithText(ctx, u) }},
+		{"images", func() (any, error) { return s.ExtractImages(ctx, u) }},
+		{"tables", func() (any, error) { return s.ExtractTables(ctx, u) }},
+		{"outline", func() (any, error) { return s.ExtractOutline(ctx, u) }},
+		{"markdown", func() (any, error) { return s.ExtractMarkdown(ctx, u) }},
+		{"paragraphs", func() (any, error) { return s.ExtractParagraphs(ctx, u) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.extract()
+			if err != nil {
+				t.Fatal(err)
+			}
+			text := strings.ToLower(fmt.Sprint(got))
+			if !strings.Contains(text, "top") {
+				t.Errorf("got %v, want the content above the limit", got)
+			}
+			if strings.Contains(text, "deep") {
+				t.Errorf("got %v, want nothing past the limit", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package scraper
+
+import (
+	"context"
+	"net/url"
+	"slices"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// Extracts the absolute URLs of all links on a page, without duplicates
+func ExtractLinks(u string) ([]string, error) {
+	return defaultScraper.ExtractLinks(context.Background(), u)
+}
+
+// Extracts the absolute URLs of all links on the page at u, without duplicates
+func (s *Scraper) ExtractLinks(ctx context.Context, u string) ([]string, error) {
+	doc, pageURL, err := s.fetchDocument(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+	return linksFromHTML(doc, pageURL, s.extract.maxResults, s.extract.followedOnly, s.extract), nil
+}
+
+// Collects the page's links in document order, stopping once it has limit of
+// them if limit is above 0, and leaving out nofollow links if followedOnly.
+// Only o's depth and node limits apply.
+func linksFromHTML(doc *html.Node, pageURL *url.URL, limit int, followedOnly bool, o *extractOptions) []string {
+	base := baseURL(doc, pageURL)
+	seen := make(map[string]bool)
+	var links []string
+
+	lim := o.newWalkLimits()
+	var traverse func(*html.Node)
+	traverse = func(n *html.Node) {
+		if !lim.enter() {
+			return
+		}
+		defer lim.leave()
+		if limit > 0 && len(links) >= limit {
+			return
+		}
+		if isElement(n, "a") && !(followedOnly && hasToken(getAttr(n, "rel"), "nofollow")) {
+			if link, ok := resolveLink(base, getAttr(n, "href")); ok && !seen[link] {
+				seen[link] = true
+				links = append(links, link)
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			traverse(c)
+		}
+	}
+
+	traverse(doc)
+
+	return links
+}
+
+// A link on a page along with the text it's shown as
+type Link struct {
+	URL string `json:"url"`
+	// Visible text of the anchor, empty for e.g. image links
+	Text string `json:"text"`
+	// The link's rel includes "nofollow", asking crawlers not to follow it
+	// or pass it ranking
+	NoFollow bool `json:"nofollow"`
+	// The lower-case values of the rel attribute, such as "sponsored" or
+	// "ugc"
+	Rel []string `json:"rel,omitempty"`
+}
+
+// Extracts every link on a page with its anchor text. Unlike ExtractLinks,
+// a URL linked several times is returned once per anchor.
+func ExtractLinksWithText(u string) ([]Link, error) {
+	return defaultScraper.ExtractLinksWithText(context.Background(), u)
+}
+
+// Extracts every link on the page at u with its anchor text
+func (s *Scraper) ExtractLinksWithText(ctx context.Context, u string) ([]Link, error) {
+	doc, pageURL, err := s.fetchDocument(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+	return linksWithTextFromHTML(doc, pageURL, s.extract), nil
+}
+
+func linksWithTextFromHTML(doc *html.Node, pageURL *url.URL, o *extractOptions) []Link {
+	base := baseURL(doc, pageURL)
+	var links []Link
+
+	lim := o.newWalkLimits()
+	var traverse func(*html.Node)
+	traverse = func(n *html.Node) {
+		if !lim.enter() {
+			return
+		}
+		defer lim.leave()
+		if o.maxResults > 0 && len(links) >= o.maxResults {
+			return
+		}
+		if isElement(n, "a") {
+			rel := strings.Fields(strings.ToLower(getAttr(n, "rel")))
+			nofollow := slices.Contains(rel, "nofollow")
+			if link, ok := resolveLink(base, getAttr(n, "href")); ok && !(nofollow && o.followedOnly) {
+				links = append(links, Link{URL: link, Text: textFromNode(n, o), NoFollow: nofollow, Rel: rel})
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			traverse(c)
+		}
+	}
+
+	traverse(doc)
+
+	return links
+}
+
+// Resolves href against base, skipping empty, fragment-only and
+// non-navigational (javascript:, mailto: ...) links
+func resolveLink(base *url.URL, href string) (string, bool) {
+	href = strings.TrimSpace(href)
+	if href == "" || strings.HasPrefix(href, "#") {
+		return "", false
+	}
+
+	ref, err := url.Parse(href)
+	if err != nil {
+		return "", false
+	}
+	abs := base.ResolveReference(ref)
+	if abs.Scheme != "http" && abs.Scheme != "https" {
+		return "", false
+	}
+
+	abs.Fragment = ""
+	return abs.String(), true
+}
@@ -0,0 +1,167 @@
+package scraper
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+)
+
+// Patterns in class and id attributes that hint at content or boilerplate,
+// as used by the readability algorithm
+var (
+	positiveHint = regexp.MustCompile(`(?i)article|body|content|entry|hentry|main|page|post|text|blog|story`)
+	negativeHint = regexp.MustCompile(`(?i)banner|combx|comment|contact|foot|footer|footnote|masthead|media|meta|menu|nav|outbrain|promo|related|scroll|share|shoutbox|sidebar|skyscraper|sponsor|shopping|tags|tool|widget|ad-|advert`)
+)
+
+// Paragraphs shorter than this don't count towards their container's score
+const minParagraphChars = 25
+
+// Extracts the text of a page's main content block, leaving out navigation,
+// footers and other boilerplate
+func ExtractMainContent(u string) (string, error) {
+	return defaultScraper.ExtractMainContent(context.Background(), u)
+}
+
+// Extracts the text of the main content block of the page at u
+func (s *Scraper) ExtractMainContent(ctx context.Context, u string) (string, error) {
+	doc, _, err := s.fetchDocument(ctx, u)
+	if err != nil {
+		return "", err
+	}
+	return textFromNode(mainContentNode(doc, s.extract), s.extract), nil
+}
+
+// Picks the element most likely to hold the page's main content. Text
+// blocks award points to their parent and grandparent, so the winner is the
+// container with the most substantial, least link-heavy text. Falls back to
+// <body> (or the whole document) when nothing scores.
+func mainContentNode(doc *html.Node, o *extractOptions) *html.Node {
+	stats := make(map[*html.Node]textStats)
+	collectTextStats(doc, stats, false, o)
+
+	scores := make(map[*html.Node]float64)
+	var candidates []*html.Node
+	addScore := func(n *html.Node, score float64) {
+		if n == nil || n.Type != html.ElementNode {
+			return
+		}
+		if _, ok := scores[n]; !ok {
+			scores[n] = initialScore(n)
+			candidates = append(candidates, n)
+		}
+		scores[n] += score
+	}
+
+	var traverse func(*html.Node)
+	traverse = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			if o.isIgnorable(n) {
+				return
+			}
+			switch n.Data {
+			case "p", "pre", "td", "blockquote":
+				text := textFromNode(n, o)
+				if utf8.RuneCountInString(text) >= minParagraphChars {
+					score := 1 + float64(strings.Count(text, ","))
+					score += min(float64(utf8.RuneCountInString(text))/100, 3)
+					addScore(n.Parent, score)
+					if n.Parent != nil {
+						addScore(n.Parent.Parent, score/2)
+					}
+				}
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			traverse(c)
+		}
+	}
+	traverse(doc)
+
+	var best *html.Node
+	var bestScore float64
+	for _, n := range candidates {
+		score := scores[n] * (1 - stats[n].linkDensity())
+		if best == nil || score > bestScore {
+			best, bestScore = n, score
+		}
+	}
+	if best != nil {
+		return best
+	}
+
+	if body := findElement(doc, "body"); body != nil {
+		return body
+	}
+	return doc
+}
+
+// Starting score for a candidate, based on its tag and class/id hints
+func initialScore(n *html.Node) float64 {
+	var score float64
+	switch n.Data {
+	case "div", "article", "main", "section":
+		score += 5
+	case "pre", "td", "blockquote":
+		score += 3
+	case "address", "ol", "ul", "dl", "dd", "dt", "li", "form":
+		score -= 3
+	case "h1", "h2", "h3", "h4", "h5", "h6", "th":
+		score -= 5
+	}
+
+	for _, attr := range []string{getAttr(n, "class"), getAttr(n, "id")} {
+		if attr == "" {
+			continue
+		}
+		if negativeHint.MatchString(attr) {
+			score -= 25
+		}
+		if positiveHint.MatchString(attr) {
+			score += 25
+		}
+	}
+	return score
+}
+
+// Amount of visible text under an element, and how much of it is link text
+type textStats struct {
+	chars     int
+	linkChars int
+}
+
+func (t textStats) linkDensity() float64 {
+	if t.chars == 0 {
+		return 0
+	}
+	return float64(t.linkChars) / float64(t.chars)
+}
+
+// Fills stats for n and every element below it, returning n's own totals
+func collectTextStats(n *html.Node, stats map[*html.Node]textStats, inLink bool, o *extractOptions) textStats {
+	var t textStats
+	if o.isHidden(n) || o.isIgnorable(n) {
+		return t
+	}
+	if n.Type == html.TextNode {
+		chars := utf8.RuneCountInString(normalizeSpace(n.Data))
+		t.chars = chars
+		if inLink {
+			t.linkChars = chars
+		}
+		return t
+	}
+
+	inLink = inLink || isElement(n, "a")
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		ct := collectTextStats(c, stats, inLink, o)
+		t.chars += ct.chars
+		t.linkChars += ct.linkChars
+	}
+	if n.Type == html.ElementNode {
+		stats[n] = t
+	}
+	return t
+}
@@ -0,0 +1,51 @@
+package scraper
+
+import (
+	"context"
+	"net/url"
+
+	"golang.org/x/net/html"
+)
+
+// Images narrower or shorter than this, by their attributes, are taken for
+// icons or tracking pixels rather than a page's main image
+const minMainImageSize = 100
+
+// Finds the URL of a page's main image, as for a thumbnail: its og:image,
+// else its twitter:image, else the largest image in its main content.
+// Returns "" if there is none.
+func ExtractMainImage(u string) (string, error) {
+	return defaultScraper.ExtractMainImage(context.Background(), u)
+}
+
+// Finds the URL of the main image of the page at u
+func (s *Scraper) ExtractMainImage(ctx context.Context, u string) (string, error) {
+	doc, pageURL, err := s.fetchDocument(ctx, u)
+	if err != nil {
+		return "", err
+	}
+	return mainImageFromHTML(doc, pageURL, s.extract), nil
+}
+
+func mainImageFromHTML(doc *html.Node, pageURL *url.URL, o *extractOptions) string {
+	base := baseURL(doc, pageURL)
+	social := socialMetaFromHTML(doc, pageURL)
+	for _, img := range []string{social.Image, social.TwitterImage} {
+		if abs, ok := resolveLink(base, img); ok {
+			return abs
+		}
+	}
+
+	// Images without dimensions count as smaller than any with them, so
+	// they're only picked when there is nothing else
+	best, bestArea := "", -1
+	for _, img := range imagesUnder(mainContentNode(doc, o), base, 0, o) {
+		if (img.Width > 0 && img.Width < minMainImageSize) || (img.Height > 0 && img.Height < minMainImageSize) {
+			continue
+		}
+		if area := img.Width * img.Height; area > bestArea {
+			best, bestArea = img.Src, area
+		}
+	}
+	return best
+}
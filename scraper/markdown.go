@@ -0,0 +1,199 @@
+package scraper
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// Extracts the content of an HTML page as Markdown, keeping headings,
+// links, lists, emphasis and paragraphs
+func ExtractMarkdown(u string) (string, error) {
+	return defaultScraper.ExtractMarkdown(context.Background(), u)
+}
+
+// Extracts the content of the page at u as Markdown
+func (s *Scraper) ExtractMarkdown(ctx context.Context, u string) (string, error) {
+	doc, pageURL, err := s.fetchDocument(ctx, u)
+	if err != nil {
+		return "", err
+	}
+	return markdownFromHTML(doc, baseURL(doc, pageURL), s.extract), nil
+}
+
+func markdownFromHTML(doc *html.Node, base *url.URL, o *extractOptions) string {
+	var blocks, words []string
+	var prefix string
+	// Inside links and emphasis, block elements don't break the block
+	inline := 0
+	// One entry per open list, counting items for <ol>; -1 means <ul>
+	var lists []int
+	dedupe := o.newDeduper()
+
+	// The prefix is kept until a block uses it, so an item whose text sits
+	// in a <p> still gets its marker
+	flush := func() {
+		if len(words) > 0 {
+			blocks = append(blocks, prefix+strings.Join(words, " "))
+			words = nil
+			prefix = ""
+		}
+	}
+	// Renders the children of n on their own and returns the result
+	renderInline := func(n *html.Node, traverse func(*html.Node)) string {
+		saved := words
+		words = nil
+		inline++
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			traverse(c)
+		}
+		inline--
+		text := strings.Join(words, " ")
+		words = saved
+		return text
+	}
+
+	lim := o.newWalkLimits()
+	var traverse func(*html.Node)
+	traverse = func(n *html.Node) {
+		if !lim.enter() {
+			return
+		}
+		defer lim.leave()
+		if o.isHidden(n) || o.isIgnorable(n) {
+			return
+		}
+		if n.Type == html.TextNode {
+			if text := normalizeSpace(n.Data); text != "" && !dedupe.skip(text) {
+				words = append(words, text)
+			}
+			return
+		}
+		if n.Type != html.ElementNode {
+			for c := n.FirstChild; c != nil; c = c.NextSibling {
+				traverse(c)
+			}
+			return
+		}
+
+		switch n.Data {
+		case "a":
+			text := renderInline(n, traverse)
+			if link, ok := resolveLink(base, getAttr(n, "href")); ok && text != "" {
+				text = "[" + text + "](" + link + ")"
+			}
+			if text != "" {
+				words = append(words, text)
+			}
+			return
+		case "strong", "b":
+			if text := renderInline(n, traverse); text != "" {
+				words = append(words, "**"+text+"**")
+			}
+			return
+		case "em", "i":
+			if text := renderInline(n, traverse); text != "" {
+				words = append(words, "*"+text+"*")
+			}
+			return
+		}
+
+		if inline > 0 {
+			for c := n.FirstChild; c != nil; c = c.NextSibling {
+				traverse(c)
+			}
+			return
+		}
+
+		switch n.Data {
+		case "h1", "h2", "h3", "h4", "h5", "h6":
+			flush()
+			prefix = strings.Repeat("#", int(n.Data[1]-'0')) + " "
+		case "ul", "ol":
+			flush()
+			if n.Data == "ol" {
+				lists = append(lists, 0)
+			} else {
+				lists = append(lists, -1)
+			}
+		case "li":
+			flush()
+			prefix = listItemPrefix(lists)
+		case "br":
+			flush()
+		default:
+			if blockElements[n.Data] {
+				flush()
+			}
+		}
+
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			traverse(c)
+		}
+
+		switch n.Data {
+		case "ul", "ol":
+			flush()
+			lists = lists[:len(lists)-1]
+		case "li", "h1", "h2", "h3", "h4", "h5", "h6":
+			// An empty item or heading mustn't mark whatever follows it
+			flush()
+			prefix = ""
+		default:
+			if blockElements[n.Data] {
+				flush()
+			}
+		}
+	}
+
+	traverse(doc)
+	flush()
+
+	return joinMarkdownBlocks(blocks)
+}
+
+// Starts another item in the innermost of the open lists, returning its
+// marker, indented for nesting. lists has one entry per open list, counting
+// items for <ol>; -1 means <ul>.
+func listItemPrefix(lists []int) string {
+	indent := ""
+	if len(lists) > 1 {
+		indent = strings.Repeat("  ", len(lists)-1)
+	}
+	if len(lists) == 0 || lists[len(lists)-1] < 0 {
+		return indent + "- "
+	}
+	lists[len(lists)-1]++
+	return indent + strconv.Itoa(lists[len(lists)-1]) + ". "
+}
+
+// Separates blocks with blank lines, except between items of the same list
+func joinMarkdownBlocks(blocks []string) string {
+	var b strings.Builder
+	for i, block := range blocks {
+		if i > 0 {
+			if isListItem(block) && isListItem(blocks[i-1]) {
+				b.WriteString("\n")
+			} else {
+				b.WriteString("\n\n")
+			}
+		}
+		b.WriteString(block)
+	}
+	return b.String()
+}
+
+func isListItem(block string) bool {
+	block = strings.TrimLeft(block, " ")
+	if strings.HasPrefix(block, "- ") {
+		return true
+	}
+	i := 0
+	for i < len(block) && block[i] >= '0' && block[i] <= '9' {
+		i++
+	}
+	return i > 0 && strings.HasPrefix(block[i:], ". ")
+}
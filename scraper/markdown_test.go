@@ -0,0 +1,43 @@
+package scraper_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/charlescqian/go-scrape/scraper"
+	"github.com/charlescqian/go-scrape/scraper/scrapetest"
+)
+
+func TestExtractMarkdown(t *testing.T) {
+	tests := []struct {
+		name string
+		html string
+		want string
+	}{
+		{"headings", "<h1>Title</h1><p>Intro</p><h3>Part</h3>", "# Title\n\nIntro\n\n### Part"},
+		{"emphasis", "<p><b>Bold</b> and <strong>strong</strong> and <em>slanted</em> <i>text</i></p>", "**Bold** and **strong** and *slanted* *text*"},
+		{"links", `<p>See <a href="/docs">the docs</a> or <a href="#top">top</a></p>`, "See [the docs](https://example.com/docs) or top"},
+		{"emphasis in a link", `<a href="/a"><b>bold</b> link</a>`, "[**bold** link](https://example.com/a)"},
+		{"unordered list", "<ul><li>one</li><li>two</li></ul><p>after</p>", "- one\n- two\n\nafter"},
+		{"ordered list", "<ol><li>one</li><li>two</li></ol>", "1. one\n2. two"},
+		{"nested lists", "<ul><li>a<ol><li>b</li><li>c</li></ol></li><li>d</li></ul>", "- a\n  1. b\n  2. c\n- d"},
+		{"empty item", "<ul><li></li></ul><p>after</p>", "after"},
+		{"line break", "<p>one<br>two</p>", "one\n\ntwo"},
+		{"scripts", "<p>shown</p><script>hidden()</script>", "shown"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := scraper.NewScraper(scraper.WithFetcher(scrapetest.Pages{"https://example.com/": tt.html}))
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := s.ExtractMarkdown(context.Background(), "https://example.com/")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("ExtractMarkdown() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
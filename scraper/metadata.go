@@ -0,0 +1,193 @@
+package scraper
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// Descriptive information about a page, taken from its <head>
+type PageMetadata struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Author      string `json:"author"`
+	// Absolute URLs, resolved against the page like Alternates
+	Canonical string `json:"canonical"`
+	OGImage   string `json:"og_image"`
+	// From <meta name="keywords">, split at commas
+	Keywords []string `json:"keywords,omitempty"`
+	// Absolute URL of the page's icon, see ExtractFavicon
+	Favicon string `json:"favicon"`
+	// ISO 639-1 code from <html lang>, or detected from the text
+	Language string `json:"language"`
+	// The <html lang> attribute as written, e.g. "en-US", or empty if the
+	// page doesn't declare one
+	HTMLLang string `json:"html_lang"`
+	// Absolute URLs of the page's translations, by hreflang, from
+	// <link rel="alternate" hreflang="..."> tags. The first link wins if a
+	// language is listed twice.
+	Alternates map[string]string `json:"alternates,omitempty"`
+}
+
+// Extracts the title, description, canonical URL and og:image of a page
+func ExtractMetadata(u string) (*PageMetadata, error) {
+	return defaultScraper.ExtractMetadata(context.Background(), u)
+}
+
+// Extracts the title, description, canonical URL and og:image of the page at u
+func (s *Scraper) ExtractMetadata(ctx context.Context, u string) (*PageMetadata, error) {
+	doc, pageURL, err := s.fetchDocument(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+	return metadataFromHTML(doc, pageURL, s.extract), nil
+}
+
+func metadataFromHTML(doc *html.Node, pageURL *url.URL, o *extractOptions) *PageMetadata {
+	var m PageMetadata
+	base := baseURL(doc, pageURL)
+
+	var traverse func(*html.Node)
+	traverse = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			switch n.Data {
+			case "title":
+				if m.Title == "" && n.FirstChild != nil {
+					m.Title = strings.TrimSpace(n.FirstChild.Data)
+				}
+			case "meta":
+				content := strings.TrimSpace(getAttr(n, "content"))
+				name := strings.ToLower(strings.TrimSpace(getAttr(n, "name")))
+				switch {
+				case name == "description" && m.Description == "":
+					m.Description = content
+				case name == "author" && m.Author == "":
+					m.Author = content
+				case name == "keywords" && m.Keywords == nil:
+					m.Keywords = splitKeywords(content)
+				}
+				if m.OGImage == "" && metaName(n) == "og:image" {
+					if link, ok := resolveLink(base, content); ok {
+						m.OGImage = link
+					}
+				}
+			case "link":
+				rel := getAttr(n, "rel")
+				if m.Canonical == "" && hasToken(rel, "canonical") {
+					if link, ok := resolveLink(base, getAttr(n, "href")); ok {
+						m.Canonical = link
+					}
+				}
+				lang := strings.TrimSpace(getAttr(n, "hreflang"))
+				if lang != "" && hasToken(rel, "alternate") {
+					if link, ok := resolveLink(base, getAttr(n, "href")); ok && m.Alternates[lang] == "" {
+						if m.Alternates == nil {
+							m.Alternates = make(map[string]string)
+						}
+						m.Alternates[lang] = link
+					}
+				}
+			case "svg":
+				// <title> inside inline SVG isn't the page title
+				return
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			traverse(c)
+		}
+	}
+
+	traverse(doc)
+	m.Favicon = faviconFromHTML(doc, pageURL)
+	m.HTMLLang = htmlLang(doc)
+	m.Language = pageLanguage(doc, o)
+
+	return &m
+}
+
+// Splits a comma-separated keywords list, trimming each keyword and
+// dropping empty ones
+func splitKeywords(s string) []string {
+	var keywords []string
+	for _, k := range strings.Split(s, ",") {
+		if k = normalizeSpace(k); k != "" {
+			keywords = append(keywords, k)
+		}
+	}
+	return keywords
+}
+
+// The OpenGraph and Twitter Card tags of a page, as used for link previews
+type SocialMeta struct {
+	Title        string `json:"title"`
+	Description  string `json:"description"`
+	Image        string `json:"image"`
+	URL          string `json:"url"`
+	Type         string `json:"type"`
+	TwitterCard  string `json:"twitter_card"`
+	TwitterImage string `json:"twitter_image"`
+}
+
+// Extracts the OpenGraph (og:*) and Twitter Card tags of a page. Tags the
+// page doesn't have are left empty.
+func ExtractSocialMeta(u string) (*SocialMeta, error) {
+	return defaultScraper.ExtractSocialMeta(context.Background(), u)
+}
+
+// Extracts the OpenGraph and Twitter Card tags of the page at u
+func (s *Scraper) ExtractSocialMeta(ctx context.Context, u string) (*SocialMeta, error) {
+	doc, pageURL, err := s.fetchDocument(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+	return socialMetaFromHTML(doc, pageURL), nil
+}
+
+func socialMetaFromHTML(doc *html.Node, pageURL *url.URL) *SocialMeta {
+	var m SocialMeta
+	fields := map[string]*string{
+		"og:title":       &m.Title,
+		"og:description": &m.Description,
+		"og:image":       &m.Image,
+		"og:url":         &m.URL,
+		"og:type":        &m.Type,
+		"twitter:card":   &m.TwitterCard,
+		"twitter:image":  &m.TwitterImage,
+	}
+
+	var traverse func(*html.Node)
+	traverse = func(n *html.Node) {
+		if isElement(n, "meta") {
+			if f, ok := fields[metaName(n)]; ok && *f == "" {
+				*f = strings.TrimSpace(getAttr(n, "content"))
+			}
+		}
+		if isElement(n, "svg") {
+			return
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			traverse(c)
+		}
+	}
+	traverse(doc)
+
+	// Previews need absolute URLs, though sites don't always give them
+	base := baseURL(doc, pageURL)
+	for _, f := range []*string{&m.Image, &m.URL, &m.TwitterImage} {
+		if abs, ok := resolveLink(base, *f); ok {
+			*f = abs
+		}
+	}
+	return &m
+}
+
+// Returns the key of a <meta> tag: its property, as OpenGraph uses, or else
+// its name, as Twitter Cards do (though sites mix them up)
+func metaName(n *html.Node) string {
+	if p := getAttr(n, "property"); p != "" {
+		return strings.ToLower(p)
+	}
+	return strings.ToLower(getAttr(n, "name"))
+}
@@ -0,0 +1,60 @@
+package scraper_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/charlescqian/go-scrape/scraper"
+	"github.com/charlescqian/go-scrape/scraper/scrapetest"
+)
+
+func TestExtractMetadataURLs(t *testing.T) {
+	tests := []struct {
+		name                            string
+		head                            string
+		canonical, ogImage, alternateFR string
+	}{
+		{
+			name: "relative",
+			head: `<link rel="canonical" href="/post"><meta property="og:image" content="img/cover.png">` +
+				`<link rel="alternate" hreflang="fr" href="/fr/post">`,
+			canonical:   "https://example.com/post",
+			ogImage:     "https://example.com/blog/img/cover.png",
+			alternateFR: "https://example.com/fr/post",
+		},
+		{
+			name:      "absolute",
+			head:      `<link rel="canonical" href="https://example.org/post"><meta property="og:image" content="https://cdn.example.org/a.png">`,
+			canonical: "https://example.org/post",
+			ogImage:   "https://cdn.example.org/a.png",
+		},
+		{
+			name:      "base element",
+			head:      `<base href="https://static.example.com/"><link rel="canonical" href="post"><meta property="og:image" content="a.png">`,
+			canonical: "https://static.example.com/post",
+			ogImage:   "https://static.example.com/a.png",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			const u = "https://example.com/blog/post"
+			s, err := scraper.NewScraper(scraper.WithFetcher(scrapetest.Pages{u: "<html><head>" + tt.head + "</head><body></body></html>"}))
+			if err != nil {
+				t.Fatal(err)
+			}
+			m, err := s.ExtractMetadata(context.Background(), u)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if m.Canonical != tt.canonical {
+				t.Errorf("Canonical = %q, want %q", m.Canonical, tt.canonical)
+			}
+			if m.OGImage != tt.ogImage {
+				t.Errorf("OGImage = %q, want %q", m.OGImage, tt.ogImage)
+			}
+			if m.Alternates["fr"] != tt.alternateFR {
+				t.Errorf("Alternates[fr] = %q, want %q", m.Alternates["fr"], tt.alternateFR)
+			}
+		})
+	}
+}
@@ -0,0 +1,330 @@
+package scraper
+
+import (
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// Elements whose text is never part of the visible content by default
+var defaultIgnoredTags = []string{"script", "style", "head", "noscript"}
+
+// Nesting deeper than this is almost certainly broken or hostile markup
+const defaultMaxDepth = 512
+
+// Settings that control how text is pulled out of a parsed document
+type extractOptions struct {
+	ignored map[string]bool
+	dedupe  DedupeMode
+	// Written between separate runs of text, and between the text either
+	// side of a <br> if lineBreak isn't set
+	separator    string
+	lineBreak    string
+	hasLineBreak bool
+	// Collapse the whitespace in each text node and trim its ends
+	trimEach bool
+	// Limits on how deep and how much of the document is walked, 0 for none
+	maxDepth int
+	maxNodes int
+	// Skip elements hidden with the hidden attribute, aria-hidden or an
+	// inline style
+	skipHidden bool
+	// Write the alt text of images where they appear
+	imageAlt bool
+	// Mark list items with "- " or their number in structured text
+	listMarkers bool
+	// Decode entities left in text after parsing, such as "&amp;"
+	unescape bool
+	// Join the text of adjacent inline elements instead of treating them
+	// as word boundaries like any other element
+	joinInline bool
+	// Put the text of <main> or <article> before the rest of the page
+	semanticOrder bool
+	// Most links or images to return, 0 for all
+	maxResults int
+	// Leave rel="nofollow" links out of extracted links
+	followedOnly bool
+}
+
+// Used by the reader-based functions, which have no Scraper to configure them
+var defaultExtractOptions = newExtractOptions()
+
+func newExtractOptions() *extractOptions {
+	o := &extractOptions{
+		ignored:    make(map[string]bool),
+		separator:  " ",
+		trimEach:   true,
+		maxDepth:   defaultMaxDepth,
+		skipHidden: true,
+	}
+	for _, tag := range defaultIgnoredTags {
+		o.ignored[tag] = true
+	}
+	return o
+}
+
+// Adds to the elements left out of extraction along with everything in
+// them, e.g. "nav", "footer" or "aside"
+func WithIgnoredTags(tags ...string) Option {
+	return func(s *Scraper) {
+		for _, tag := range tags {
+			s.extract.ignored[tag] = true
+		}
+	}
+}
+
+// Replaces the default set of skipped elements (script, style, head and
+// noscript) with tags
+func WithOnlyIgnoredTags(tags ...string) Option {
+	return func(s *Scraper) {
+		s.extract.ignored = make(map[string]bool, len(tags))
+		for _, tag := range tags {
+			s.extract.ignored[tag] = true
+		}
+	}
+}
+
+// Sets what is written between separate runs of text, such as the contents
+// of two paragraphs or words either side of a space. Defaults to a space;
+// "\n" puts each run on its own line and "" runs them together.
+func WithSeparator(sep string) Option {
+	return func(s *Scraper) {
+		s.extract.separator = sep
+	}
+}
+
+// Sets whether each text node has its whitespace collapsed and trimmed,
+// which is the default. Turned off, text nodes are written as they appear
+// in the source and the separator only goes between elements.
+func WithTrimEach(trim bool) Option {
+	return func(s *Scraper) {
+		s.extract.trimEach = trim
+	}
+}
+
+// Sets what a <br> becomes in extracted text. Defaults to the separator, so
+// with the default space the text stays on one line; pass "\n" to keep the
+// line breaks.
+func WithLineBreakSeparator(sep string) Option {
+	return func(s *Scraper) {
+		s.extract.lineBreak = sep
+		s.extract.hasLineBreak = true
+	}
+}
+
+func (o *extractOptions) lineBreakSeparator() string {
+	if o.hasLineBreak {
+		return o.lineBreak
+	}
+	return o.separator
+}
+
+// Stops text extraction from descending more than depth elements into the
+// document, returning ErrTruncated with the text so far. Links, images,
+// tables, Markdown, outlines and paragraphs stop there too, returning what
+// they found above it. Defaults to 512; 0 removes the limit.
+func WithMaxDepth(depth int) Option {
+	return func(s *Scraper) {
+		s.extract.maxDepth = depth
+	}
+}
+
+// Stops text extraction after visiting n nodes, returning ErrTruncated with
+// the text so far. Like WithMaxDepth, it also bounds the other extractors
+// that walk the page. Unlimited by default.
+func WithMaxNodes(n int) Option {
+	return func(s *Scraper) {
+		s.extract.maxNodes = n
+	}
+}
+
+// Limits ExtractLinks, ExtractLinksWithText and ExtractImages to the first
+// n results in document order, stopping the search there. Unlimited by
+// default.
+func WithMaxResults(n int) Option {
+	return func(s *Scraper) {
+		s.extract.maxResults = n
+	}
+}
+
+// Sets whether ExtractLinks and ExtractLinksWithText leave out links marked
+// rel="nofollow". Off by default.
+func WithFollowedLinksOnly(followed bool) Option {
+	return func(s *Scraper) {
+		s.extract.followedOnly = followed
+	}
+}
+
+// Sets whether entities still present in a page's text after parsing are
+// decoded, for pages that escape their text twice so that "Tom &amp;amp;
+// Jerry" would otherwise come out as "Tom &amp; Jerry". Each piece of text
+// is decoded once, so text that legitimately reads "&amp;" after one round
+// of decoding is changed too. Off by default.
+func WithUnescapeEntities(unescape bool) Option {
+	return func(s *Scraper) {
+		s.extract.unescape = unescape
+	}
+}
+
+// Sets whether structured text and paragraphs keep list structure, putting
+// each <li> on its own line after "- " or, in an <ol>, its number, indented
+// two spaces per level of nesting. A whole list then makes up one
+// paragraph. Off by default.
+func WithListMarkers(mark bool) Option {
+	return func(s *Scraper) {
+		s.extract.listMarkers = mark
+	}
+}
+
+// Sets whether the page's main content, its <main> element or else its
+// <article> elements, is extracted first, ahead of headers, navigation and
+// anything else around it, which follows in document order. Nothing is left
+// out. Off by default, extracting everything in document order.
+func WithSemanticOrder(enabled bool) Option {
+	return func(s *Scraper) {
+		s.extract.semanticOrder = enabled
+	}
+}
+
+// Sets whether text in adjacent inline elements such as <b>, <span> or <a>
+// is joined as it displays, so "<b>pre</b><b>fix</b>" reads "prefix". Off
+// by default, where every element boundary separates words, giving
+// "pre fix".
+func WithJoinInline(join bool) Option {
+	return func(s *Scraper) {
+		s.extract.joinInline = join
+	}
+}
+
+// Sets whether an image's alt text is included in extracted text where the
+// image appears. Off by default; images without alt text are always
+// skipped.
+func WithImageAlt(include bool) Option {
+	return func(s *Scraper) {
+		s.extract.imageAlt = include
+	}
+}
+
+// Sets whether the text of hidden elements is skipped, which it is by
+// default; pass false to get everything. An element counts as hidden if it
+// has the hidden attribute, aria-hidden="true", or an inline display:none
+// or visibility:hidden style. Stylesheets aren't taken into account.
+func WithSkipHidden(skip bool) Option {
+	return func(s *Scraper) {
+		s.extract.skipHidden = skip
+	}
+}
+
+// Tracks a recursive walk of the document against the depth and node
+// limits, so extractors other than writeText can't be led too deep either
+type walkLimits struct {
+	maxDepth, maxNodes int
+	depth, nodes       int
+}
+
+func (o *extractOptions) newWalkLimits() *walkLimits {
+	return &walkLimits{maxDepth: o.maxDepth, maxNodes: o.maxNodes}
+}
+
+// Reports whether the walk may go into the next node, counting it. Each
+// true must be followed by a call to leave once the node is done.
+func (l *walkLimits) enter() bool {
+	l.nodes++
+	if (l.maxDepth > 0 && l.depth > l.maxDepth) || (l.maxNodes > 0 && l.nodes > l.maxNodes) {
+		return false
+	}
+	l.depth++
+	return true
+}
+
+func (l *walkLimits) leave() {
+	l.depth--
+}
+
+// Reports whether n is one of the ignored elements, which are left out
+// along with everything in them
+func (o *extractOptions) isIgnorable(n *html.Node) bool {
+	if n == nil || n.Type != html.ElementNode {
+		return false
+	}
+	return o.ignored[n.Data]
+}
+
+// Decodes the entities left in text after parsing, if enabled
+func (o *extractOptions) unescapeText(text string) string {
+	if !o.unescape || !strings.Contains(text, "&") {
+		return text
+	}
+	return html.UnescapeString(text)
+}
+
+// Reports whether n and everything in it should be left out as hidden
+func (o *extractOptions) isHidden(n *html.Node) bool {
+	if !o.skipHidden || n.Type != html.ElementNode {
+		return false
+	}
+	for _, a := range n.Attr {
+		switch a.Key {
+		case "hidden":
+			return true
+		case "aria-hidden":
+			if strings.EqualFold(strings.TrimSpace(a.Val), "true") {
+				return true
+			}
+		case "style":
+			style := strings.ToLower(strings.Join(strings.Fields(a.Val), ""))
+			if strings.Contains(style, "display:none") || strings.Contains(style, "visibility:hidden") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Which repeated text nodes are dropped during extraction
+type DedupeMode int
+
+const (
+	// Keep every text node (the default)
+	DedupeOff DedupeMode = iota
+	// Drop a text node identical to the one emitted just before it
+	DedupeAdjacent
+	// Drop a text node identical to any emitted earlier in the document
+	DedupeGlobal
+)
+
+// Drops repeated text such as banners and cookie notices that templated
+// pages emit several times
+func WithDedupe(mode DedupeMode) Option {
+	return func(s *Scraper) {
+		s.extract.dedupe = mode
+	}
+}
+
+// Tracks emitted text for one traversal to apply the dedupe mode
+type deduper struct {
+	mode DedupeMode
+	last string
+	seen map[string]bool
+}
+
+func (o *extractOptions) newDeduper() *deduper {
+	return &deduper{mode: o.dedupe, seen: make(map[string]bool)}
+}
+
+// Reports whether text should be skipped, and records it otherwise
+func (d *deduper) skip(text string) bool {
+	switch d.mode {
+	case DedupeAdjacent:
+		if text == d.last {
+			return true
+		}
+		d.last = text
+	case DedupeGlobal:
+		if d.seen[text] {
+			return true
+		}
+		d.seen[text] = true
+	}
+	return false
+}
@@ -0,0 +1,126 @@
+package scraper
+
+import (
+	"context"
+
+	"golang.org/x/net/html"
+)
+
+// A heading of a page's outline, with the headings of lower rank that
+// follow it before the next one of its rank or higher
+type HeadingNode struct {
+	// 1 for <h1> through 6 for <h6>
+	Level int    `json:"level"`
+	Text  string `json:"text"`
+	// The id to link to the heading with, as "#" + ID, from the heading or
+	// an anchor inside it. Empty if it has none.
+	ID       string        `json:"id,omitempty"`
+	Children []HeadingNode `json:"children,omitempty"`
+}
+
+// Extracts a page's headings as a tree, as for a table of contents, in
+// document order. Levels may be skipped, so an <h3> directly under an <h1>
+// is its child. Returns an empty slice if the page has no headings.
+func ExtractOutline(u string) ([]HeadingNode, error) {
+	return defaultScraper.ExtractOutline(context.Background(), u)
+}
+
+// Extracts the headings of the page at u as a tree
+func (s *Scraper) ExtractOutline(ctx context.Context, u string) ([]HeadingNode, error) {
+	doc, _, err := s.fetchDocument(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+	return outlineFromHTML(doc, s.extract), nil
+}
+
+func outlineFromHTML(doc *html.Node, o *extractOptions) []HeadingNode {
+	var flat []HeadingNode
+	lim := o.newWalkLimits()
+	var traverse func(*html.Node)
+	traverse = func(n *html.Node) {
+		if !lim.enter() {
+			return
+		}
+		defer lim.leave()
+		if o.isHidden(n) {
+			return
+		}
+		if level := headingLevel(n); level > 0 {
+			if text := normalizeSpace(textFromNode(n, o)); text != "" {
+				flat = append(flat, HeadingNode{Level: level, Text: text, ID: headingID(n, lim)})
+			}
+			return
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			traverse(c)
+		}
+	}
+	traverse(doc)
+
+	root, _ := nestHeadings(flat, 0)
+	if root == nil {
+		return []HeadingNode{}
+	}
+	return root
+}
+
+// Nests the leading headings of flat deeper than level, returning them and
+// the count used
+func nestHeadings(flat []HeadingNode, level int) ([]HeadingNode, int) {
+	var nodes []HeadingNode
+	i := 0
+	for i < len(flat) && flat[i].Level > level {
+		h := flat[i]
+		children, used := nestHeadings(flat[i+1:], h.Level)
+		h.Children = children
+		nodes = append(nodes, h)
+		i += 1 + used
+	}
+	return nodes, i
+}
+
+// Returns n's heading level, or 0 if it isn't a heading element
+func headingLevel(n *html.Node) int {
+	if n.Type != html.ElementNode || len(n.Data) != 2 || n.Data[0] != 'h' {
+		return 0
+	}
+	if l := int(n.Data[1] - '0'); l >= 1 && l <= 6 {
+		return l
+	}
+	return 0
+}
+
+// Finds the fragment identifying heading h: its own id, or else the id or
+// name of an element inside it, as in <h2><a name="intro">Intro</a></h2>.
+// The search counts against lim, as the walk that found h does.
+func headingID(h *html.Node, lim *walkLimits) string {
+	if id := getAttr(h, "id"); id != "" {
+		return id
+	}
+	var find func(*html.Node) string
+	find = func(n *html.Node) string {
+		if !lim.enter() {
+			return ""
+		}
+		defer lim.leave()
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type != html.ElementNode {
+				continue
+			}
+			if id := getAttr(c, "id"); id != "" {
+				return id
+			}
+			if isElement(c, "a") {
+				if name := getAttr(c, "name"); name != "" {
+					return name
+				}
+			}
+			if id := find(c); id != "" {
+				return id
+			}
+		}
+		return ""
+	}
+	return find(h)
+}
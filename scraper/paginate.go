@@ -0,0 +1,111 @@
+package scraper
+
+import (
+	"context"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+const defaultMaxPages = 10
+
+// Link text and class names commonly used for "next page" links
+var (
+	nextLinkText  = regexp.MustCompile(`(?i)^(next|next page|older|older posts)?\s*(›|»|>|→)?$`)
+	nextLinkClass = regexp.MustCompile(`(?i)(^|[\s_-])(next|pagination-next|next-page)($|[\s_-])`)
+)
+
+// Controls how ExtractPaginated follows an article across pages
+type PaginateOptions struct {
+	// Most pages to fetch, including the first. Defaults to 10.
+	MaxPages int
+}
+
+// Extracts the main content of an article split over several pages,
+// following "next page" links and joining the pages with blank lines
+func ExtractPaginated(u string, opts PaginateOptions) (string, error) {
+	return defaultScraper.ExtractPaginated(context.Background(), u, opts)
+}
+
+// Extracts the main content of the article starting at u, following "next
+// page" links on the same host. If a later page fails, the text gathered so
+// far is returned along with the error.
+func (s *Scraper) ExtractPaginated(ctx context.Context, u string, opts PaginateOptions) (string, error) {
+	maxPages := opts.MaxPages
+	if maxPages <= 0 {
+		maxPages = defaultMaxPages
+	}
+
+	var pages []string
+	visited := make(map[string]bool)
+
+	for next := u; next != "" && len(pages) < maxPages; {
+		visited[next] = true
+
+		doc, pageURL, err := s.fetchDocument(ctx, next)
+		if err != nil {
+			return strings.Join(pages, "\n\n"), err
+		}
+		if text := textFromNode(mainContentNode(doc, s.extract), s.extract); text != "" {
+			pages = append(pages, text)
+		}
+		// Redirects count as visited too, so a next link back to them ends it
+		visited[pageURL.String()] = true
+
+		next = nextPageURL(doc, pageURL)
+		if visited[next] {
+			break
+		}
+	}
+
+	return strings.Join(pages, "\n\n"), nil
+}
+
+// Finds the URL of the next page, preferring rel="next" and falling back to
+// links that look like pagination. Links to other hosts are ignored.
+func nextPageURL(doc *html.Node, pageURL *url.URL) string {
+	base := baseURL(doc, pageURL)
+	var relNext, heuristic string
+
+	var traverse func(*html.Node)
+	traverse = func(n *html.Node) {
+		if relNext != "" {
+			return
+		}
+		if isElement(n, "link") || isElement(n, "a") {
+			link, ok := resolveLink(base, getAttr(n, "href"))
+			if ok && sameHost(pageURL, link) {
+				switch {
+				case hasToken(getAttr(n, "rel"), "next"):
+					relNext = link
+					return
+				case heuristic == "" && isElement(n, "a") && looksLikeNextLink(n):
+					heuristic = link
+				}
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			traverse(c)
+		}
+	}
+
+	traverse(doc)
+
+	if relNext != "" {
+		return relNext
+	}
+	return heuristic
+}
+
+func looksLikeNextLink(a *html.Node) bool {
+	if nextLinkClass.MatchString(getAttr(a, "class")) {
+		return true
+	}
+	text := textFromNode(a, defaultExtractOptions)
+	if text == "" {
+		text = getAttr(a, "aria-label")
+	}
+	return text != "" && nextLinkText.MatchString(text)
+}
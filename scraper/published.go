@@ -0,0 +1,117 @@
+package scraper
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/araddon/dateparse"
+	"golang.org/x/net/html"
+)
+
+// Meta tags holding a publication date, by the attribute naming them
+var publishedMeta = map[string][]string{
+	"property": {"article:published_time", "og:published_time"},
+	"name":     {"pubdate", "publishdate", "date", "dc.date.issued", "parsely-pub-date"},
+	"itemprop": {"datePublished"},
+}
+
+// Extracts when the article at u was published. Returns the zero time, and
+// no error, if the page doesn't say.
+func ExtractPublishDate(u string) (time.Time, error) {
+	return defaultScraper.ExtractPublishDate(context.Background(), u)
+}
+
+// Extracts when the article at u was published, or the zero time if the
+// page doesn't say
+func (s *Scraper) ExtractPublishDate(ctx context.Context, u string) (time.Time, error) {
+	doc, _, err := s.fetchDocument(ctx, u)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return publishDateFromHTML(doc), nil
+}
+
+// Looks for a publication date in meta tags, then JSON-LD, then the first
+// <time datetime> in the page
+func publishDateFromHTML(doc *html.Node) time.Time {
+	var metaDate, timeDate string
+
+	var traverse func(*html.Node)
+	traverse = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			switch n.Data {
+			case "meta":
+				if metaDate == "" && isPublishedMeta(n) {
+					metaDate = getAttr(n, "content")
+				}
+			case "time":
+				if timeDate == "" {
+					timeDate = getAttr(n, "datetime")
+				}
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			traverse(c)
+		}
+	}
+	traverse(doc)
+
+	candidates := []string{metaDate}
+	for _, obj := range jsonLDFromHTML(doc) {
+		candidates = append(candidates, jsonLDDates(obj)...)
+	}
+	candidates = append(candidates, timeDate)
+
+	for _, c := range candidates {
+		if t, ok := parseDate(c); ok {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func isPublishedMeta(n *html.Node) bool {
+	for attr, names := range publishedMeta {
+		v := getAttr(n, attr)
+		for _, name := range names {
+			if strings.EqualFold(v, name) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Collects datePublished values from a JSON-LD object, including the
+// objects of an @graph
+func jsonLDDates(obj map[string]interface{}) []string {
+	var dates []string
+	if d, ok := obj["datePublished"].(string); ok {
+		dates = append(dates, d)
+	}
+	if graph, ok := obj["@graph"].([]interface{}); ok {
+		for _, item := range graph {
+			if o, ok := item.(map[string]interface{}); ok {
+				dates = append(dates, jsonLDDates(o)...)
+			}
+		}
+	}
+	return dates
+}
+
+// Parses a date in any of the formats sites commonly use
+func parseDate(s string) (time.Time, bool) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}, false
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, true
+	}
+	t, err := dateparse.ParseAny(s)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
@@ -0,0 +1,139 @@
+package scraper
+
+import (
+	"context"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// Lazily created rate limiters, one per host
+type hostLimiters struct {
+	limit rate.Limit
+	burst int
+
+	mu       sync.Mutex
+	limiters map[string]*rate.Limiter
+}
+
+// Spaces out requests to the same host to at most perHost per second, with
+// bursts of up to burst requests. Different hosts don't limit each other.
+// With WithRobotsTxt, a host whose robots.txt sets a slower Crawl-delay is
+// held to that instead.
+func WithRateLimit(perHost rate.Limit, burst int) Option {
+	return func(s *Scraper) {
+		s.limiters = newHostLimiters(perHost, burst)
+	}
+}
+
+func newHostLimiters(limit rate.Limit, burst int) *hostLimiters {
+	return &hostLimiters{
+		limit:    limit,
+		burst:    burst,
+		limiters: make(map[string]*rate.Limiter),
+	}
+}
+
+// Returns host's limiter, creating it on first use. Must be called with
+// h.mu held.
+func (h *hostLimiters) limiter(host string) *rate.Limiter {
+	l, ok := h.limiters[host]
+	if !ok {
+		l = rate.NewLimiter(h.limit, h.burst)
+		h.limiters[host] = l
+	}
+	return l
+}
+
+// Blocks until a request to host is allowed or ctx is done
+func (h *hostLimiters) wait(ctx context.Context, host string) error {
+	h.mu.Lock()
+	l := h.limiter(host)
+	h.mu.Unlock()
+
+	return l.Wait(ctx)
+}
+
+// Lowers host's limit to one request every delay, if that is slower than
+// its current limit
+func (h *hostLimiters) slowDown(host string, delay time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	l := h.limiter(host)
+	if limit := rate.Every(delay); limit < l.Limit() {
+		l.SetLimit(limit)
+		l.SetBurst(1)
+	}
+}
+
+// Waits a random time between min and max after each request to a host
+// before the next one to it starts, so that visits aren't evenly spaced.
+// Can be combined with WithRateLimit, in which case a request waits for
+// both. Different hosts don't delay each other.
+func WithHumanizedDelay(min, max time.Duration) Option {
+	return func(s *Scraper) {
+		s.delays = &hostDelays{
+			min:  min,
+			max:  max,
+			next: make(map[string]time.Time),
+		}
+	}
+}
+
+// The earliest time the next request to each host may start
+type hostDelays struct {
+	min, max time.Duration
+
+	mu   sync.Mutex
+	next map[string]time.Time
+}
+
+// Blocks until it is host's turn for a request or ctx is done. Turns are
+// handed out in the order callers arrive.
+func (h *hostDelays) wait(ctx context.Context, host string) error {
+	h.mu.Lock()
+	now := time.Now()
+	start := h.next[host]
+	if start.Before(now) {
+		start = now
+	}
+	h.next[host] = start.Add(h.delay())
+	h.mu.Unlock()
+
+	t := time.NewTimer(start.Sub(now))
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// Draws a delay from [min, max]
+func (h *hostDelays) delay() time.Duration {
+	if h.max <= h.min {
+		return h.min
+	}
+	return h.min + rand.N(h.max-h.min+1)
+}
+
+// Waits for the rate limit and humanized delay of host, whichever are
+// configured
+func (s *Scraper) throttle(ctx context.Context, host string) error {
+	if s.limiters != nil {
+		if err := s.limiters.wait(ctx, host); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return err
+		}
+	}
+	if s.delays != nil {
+		return s.delays.wait(ctx, host)
+	}
+	return nil
+}
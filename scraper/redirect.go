@@ -0,0 +1,76 @@
+package scraper
+
+import (
+	"fmt"
+	"net/http"
+)
+
+const defaultMaxRedirects = 10
+
+func isRedirect(status int) bool {
+	switch status {
+	case http.StatusMovedPermanently, http.StatusFound, http.StatusSeeOther,
+		http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+		return true
+	}
+	return false
+}
+
+// Returns the Cookie header req will be sent with. The client only adds the
+// jar's cookies as it sends a request, after CheckRedirect has run.
+func (s *Scraper) outgoingCookies(req *http.Request) string {
+	probe := &http.Request{Header: req.Header.Clone()}
+	if s.jar != nil && s.fetcher == nil {
+		for _, c := range s.jar.Cookies(req.URL) {
+			probe.AddCookie(c)
+		}
+	}
+	return probe.Header.Get("Cookie")
+}
+
+// Limits how many redirects a single fetch may follow
+func WithMaxRedirects(n int) Option {
+	return func(s *Scraper) {
+		s.maxRedirects = n
+	}
+}
+
+// Sets whether HTTP redirects are followed, which they are by default.
+// When they aren't, a redirect response fails the fetch with a
+// RedirectError giving its Location.
+func WithFollowRedirects(follow bool) Option {
+	return func(s *Scraper) {
+		s.noRedirects = !follow
+	}
+}
+
+func (s *Scraper) checkRedirect(req *http.Request, via []*http.Request) error {
+	if s.noRedirects {
+		return http.ErrUseLastResponse
+	}
+	// Even with local files on, a remote page mustn't get to read them
+	if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+		return fmt.Errorf("%w: %s", ErrUnsafeRedirect, req.URL)
+	}
+	if s.repeatsRequest(req, via) {
+		return fmt.Errorf("%w: %s", ErrRedirectLoop, req.URL)
+	}
+	if len(via) > s.maxRedirects {
+		return fmt.Errorf("%w: stopped after %d", ErrTooManyRedirects, s.maxRedirects)
+	}
+	return nil
+}
+
+// Reports whether req would be sent exactly as one of the requests before
+// it in the chain, so following it can only go round again. Coming back to
+// a URL with different cookies isn't a loop, as when a login or consent
+// page sets one and bounces back.
+func (s *Scraper) repeatsRequest(req *http.Request, via []*http.Request) bool {
+	cookies := s.outgoingCookies(req)
+	for _, prev := range via {
+		if prev.Method == req.Method && prev.URL.String() == req.URL.String() && prev.Header.Get("Cookie") == cookies {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,83 @@
+package scraper_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/charlescqian/go-scrape/scraper"
+)
+
+// A page that sends visitors without a session cookie off to get one,
+// by HTTP redirect or meta refresh, and back again
+func newLoginBounce() *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/page", func(w http.ResponseWriter, r *http.Request) {
+		if _, err := r.Cookie("session"); err != nil {
+			http.Redirect(w, r, "/login", http.StatusFound)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte("<p>welcome</p>"))
+	})
+	mux.HandleFunc("/login", func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "1"})
+		http.Redirect(w, r, "/page", http.StatusFound)
+	})
+	mux.HandleFunc("/refresh", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		if _, err := r.Cookie("session"); err != nil {
+			w.Write([]byte(`<meta http-equiv="refresh" content="0; url=/refresh-login">`))
+			return
+		}
+		w.Write([]byte("<p>welcome</p>"))
+	})
+	mux.HandleFunc("/refresh-login", func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "1"})
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<meta http-equiv="refresh" content="0; url=/refresh">`))
+	})
+	mux.HandleFunc("/loop", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/loop-back", http.StatusFound)
+	})
+	mux.HandleFunc("/loop-back", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/loop", http.StatusFound)
+	})
+	return httptest.NewServer(mux)
+}
+
+func TestRedirectLoops(t *testing.T) {
+	srv := newLoginBounce()
+	defer srv.Close()
+
+	tests := []struct {
+		name    string
+		path    string
+		opts    []scraper.Option
+		wantErr error
+	}{
+		{"redirect bounce setting a cookie", "/page", []scraper.Option{scraper.WithCookieJar()}, nil},
+		{"redirect bounce without a jar", "/page", nil, scraper.ErrRedirectLoop},
+		{"refresh bounce setting a cookie", "/refresh", []scraper.Option{scraper.WithCookieJar()}, nil},
+		{"refresh bounce without a jar", "/refresh", nil, scraper.ErrRedirectLoop},
+		{"redirect loop", "/loop", []scraper.Option{scraper.WithCookieJar()}, scraper.ErrRedirectLoop},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]scraper.Option{scraper.WithMetaRefresh(time.Second)}, tt.opts...)
+			s, err := scraper.NewScraper(opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			text, err := s.ExtractText(srv.URL + tt.path)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ExtractText() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && text != "welcome" {
+				t.Errorf("ExtractText() = %q, want %q", text, "welcome")
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package scraper
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html"
+)
+
+// Follows <meta http-equiv="refresh"> redirects whose delay is at most
+// maxDelay, as a browser would. Up to WithMaxRedirects of them are
+// followed per fetch, on top of any HTTP redirects.
+func WithMetaRefresh(maxDelay time.Duration) Option {
+	return func(s *Scraper) {
+		s.metaRefresh = true
+		s.metaRefreshDelay = maxDelay
+	}
+}
+
+// Returns the URL a page's meta refresh sends the browser to, if it has one
+// with a delay of at most maxDelay
+func metaRefreshTarget(doc *html.Node, pageURL *url.URL, maxDelay time.Duration) (string, bool) {
+	var content string
+
+	var traverse func(*html.Node)
+	traverse = func(n *html.Node) {
+		if content != "" {
+			return
+		}
+		if isElement(n, "meta") && strings.EqualFold(getAttr(n, "http-equiv"), "refresh") {
+			content = getAttr(n, "content")
+			return
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			traverse(c)
+		}
+	}
+	traverse(doc)
+
+	delay, target, ok := parseRefresh(content)
+	if !ok || delay > maxDelay {
+		return "", false
+	}
+	return resolveLink(baseURL(doc, pageURL), target)
+}
+
+// Parses a refresh value such as "0; url=/next". A value without a URL
+// reloads the same page, which isn't a redirect.
+func parseRefresh(content string) (time.Duration, string, bool) {
+	delayPart, rest, found := strings.Cut(content, ";")
+	if !found {
+		delayPart, rest, found = strings.Cut(content, ",")
+	}
+	if !found {
+		return 0, "", false
+	}
+
+	seconds, err := strconv.ParseFloat(strings.TrimSpace(delayPart), 64)
+	if err != nil || seconds < 0 {
+		return 0, "", false
+	}
+
+	target := strings.TrimSpace(rest)
+	if len(target) >= 3 && strings.EqualFold(target[:3], "url") {
+		if after, ok := strings.CutPrefix(strings.TrimSpace(target[3:]), "="); ok {
+			target = strings.TrimSpace(after)
+		}
+	}
+	target = strings.Trim(target, `"'`)
+	if target == "" {
+		return 0, "", false
+	}
+	return time.Duration(seconds * float64(time.Second)), target, true
+}
@@ -0,0 +1,84 @@
+package scraper
+
+import (
+	"context"
+	"net/url"
+
+	"golang.org/x/net/html"
+)
+
+// The external resources a page references, as absolute URLs in document
+// order, without duplicates within each group
+type Resources struct {
+	// From <link rel="stylesheet">
+	Stylesheets []string `json:"stylesheets"`
+	// From <script src>
+	Scripts []string `json:"scripts"`
+	// From the src and srcset of <img>, and the srcset of <picture> sources
+	Images []string `json:"images"`
+	// From <a href>
+	Links []string `json:"links"`
+}
+
+// Extracts the URLs of everything a page references, grouped by type, as
+// for archiving it along with what it needs to render
+func ExtractResources(u string) (Resources, error) {
+	return defaultScraper.ExtractResources(context.Background(), u)
+}
+
+// Extracts the URLs of everything the page at u references
+func (s *Scraper) ExtractResources(ctx context.Context, u string) (Resources, error) {
+	doc, pageURL, err := s.fetchDocument(ctx, u)
+	if err != nil {
+		return Resources{}, err
+	}
+	return resourcesFromHTML(doc, pageURL), nil
+}
+
+func resourcesFromHTML(doc *html.Node, pageURL *url.URL) Resources {
+	base := baseURL(doc, pageURL)
+	res := Resources{Stylesheets: []string{}, Scripts: []string{}, Images: []string{}, Links: []string{}}
+	// The URLs already in each group
+	type entry struct {
+		group *[]string
+		link  string
+	}
+	seen := make(map[entry]bool)
+
+	add := func(group *[]string, href string) {
+		link, ok := resolveLink(base, href)
+		if ok && !seen[entry{group, link}] {
+			seen[entry{group, link}] = true
+			*group = append(*group, link)
+		}
+	}
+	addSrcset := func(srcset string) {
+		for _, src := range parseSrcset(base, srcset) {
+			add(&res.Images, src)
+		}
+	}
+
+	var traverse func(*html.Node)
+	traverse = func(n *html.Node) {
+		switch {
+		case isElement(n, "link") && hasToken(getAttr(n, "rel"), "stylesheet"):
+			add(&res.Stylesheets, getAttr(n, "href"))
+		case isElement(n, "script"):
+			add(&res.Scripts, getAttr(n, "src"))
+		case isElement(n, "img"):
+			add(&res.Images, getAttr(n, "src"))
+			addSrcset(getAttr(n, "srcset"))
+		case isElement(n, "source") && isElement(n.Parent, "picture"):
+			addSrcset(getAttr(n, "srcset"))
+		case isElement(n, "a"):
+			add(&res.Links, getAttr(n, "href"))
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			traverse(c)
+		}
+	}
+
+	traverse(doc)
+
+	return res
+}
@@ -0,0 +1,40 @@
+package scraper
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// Details of the response a page was extracted from
+type ResponseInfo struct {
+	// URL the page was served from, after redirects
+	URL        string
+	StatusCode int
+	// Caching headers, empty or zero when the server didn't send them
+	ETag         string
+	LastModified time.Time
+	CacheControl string
+	// All of the response's headers
+	Header http.Header
+}
+
+// Extracts all visible text from a page, also returning details of the
+// response such as its ETag and Last-Modified headers
+func ExtractWithResponseInfo(u string) (text string, info ResponseInfo, err error) {
+	return defaultScraper.ExtractWithResponseInfo(context.Background(), u)
+}
+
+func newResponseInfo(resp *http.Response) ResponseInfo {
+	info := ResponseInfo{
+		URL:          resp.Request.URL.String(),
+		StatusCode:   resp.StatusCode,
+		ETag:         resp.Header.Get("ETag"),
+		CacheControl: resp.Header.Get("Cache-Control"),
+		Header:       resp.Header,
+	}
+	if t, err := http.ParseTime(resp.Header.Get("Last-Modified")); err == nil {
+		info.LastModified = t
+	}
+	return info
+}
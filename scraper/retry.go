@@ -0,0 +1,104 @@
+package scraper
+
+import (
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+// Retries requests that time out, have their connection refused or reset,
+// or get a 5xx status, up to maxAttempts in total. Failures that would
+// only happen again, such as TLS and certificate errors, aren't retried.
+// The wait before retry n is drawn from [baseDelay*2^(n-1)/2,
+// baseDelay*2^(n-1)).
+func WithRetry(maxAttempts int, baseDelay time.Duration) Option {
+	return func(s *Scraper) {
+		s.maxAttempts = maxAttempts
+		s.retryDelay = baseDelay
+	}
+}
+
+// Sends req, retrying transient failures as configured by WithRetry. The
+// final attempt's response or error is returned as-is.
+func (s *Scraper) do(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+
+	for attempt := 1; ; attempt++ {
+		if err := s.throttle(ctx, req.URL.Host); err != nil {
+			return nil, err
+		}
+		if s.breakers != nil {
+			if err := s.breakers.allow(req.URL.Host); err != nil {
+				return nil, err
+			}
+		}
+
+		resp, err := s.client.Do(req.Clone(ctx))
+		if ctx.Err() != nil {
+			if resp != nil {
+				resp.Body.Close()
+			}
+			if s.breakers != nil {
+				s.breakers.release(req.URL.Host)
+			}
+			return nil, ctx.Err()
+		}
+		if s.breakers != nil {
+			s.breakers.record(req.URL.Host, isHostFailure(resp, err))
+		}
+		if attempt >= s.maxAttempts || !isRetryable(resp, err) {
+			return resp, err
+		}
+		if resp != nil {
+			resp.Body.Close()
+		}
+
+		t := time.NewTimer(backoff(s.retryDelay, attempt))
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return nil, ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+// Reports whether a request failed in a way that may not happen on
+// another try
+func isRetryable(resp *http.Response, err error) bool {
+	if err == nil {
+		return resp.StatusCode >= 500
+	}
+	if isTLSError(err) {
+		return false
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	// A server dropping a kept-alive connection shows up as an early EOF
+	return errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNABORTED) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// Reports whether a request's failure counts against its host for the
+// circuit breaker: any error reaching it, or a 5xx status
+func isHostFailure(resp *http.Response, err error) bool {
+	if err != nil {
+		// Redirect policy violations are the page's doing, not the host's
+		return !errors.Is(err, ErrTooManyRedirects) && !errors.Is(err, ErrRedirectLoop) && !errors.Is(err, ErrUnsafeRedirect)
+	}
+	return resp.StatusCode >= 500
+}
+
+func backoff(base time.Duration, attempt int) time.Duration {
+	d := base << (attempt - 1)
+	if d <= 0 {
+		return 0
+	}
+	return d/2 + rand.N(d/2+1)
+}
@@ -0,0 +1,66 @@
+package scraper_test
+
+import (
+	"errors"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/charlescqian/go-scrape/scraper"
+)
+
+func TestRetryServerErrors(t *testing.T) {
+	var hits atomic.Int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if hits.Add(1) < 3 {
+			http.Error(w, "busy", http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte("<p>ok</p>"))
+	}))
+	defer srv.Close()
+
+	s, err := scraper.NewScraper(scraper.WithRetry(3, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text, err := s.ExtractText(srv.URL); err != nil || text != "ok" {
+		t.Errorf("ExtractText() = %q, %v, want %q", text, err, "ok")
+	}
+	if n := hits.Load(); n != 3 {
+		t.Errorf("server got %d requests, want 3", n)
+	}
+}
+
+func TestRetrySkipsTLSErrors(t *testing.T) {
+	// The server's certificate isn't trusted, so every handshake fails the
+	// same way
+	var conns atomic.Int64
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	srv.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			conns.Add(1)
+		}
+	}
+	srv.Config.ErrorLog = log.New(io.Discard, "", 0)
+	srv.StartTLS()
+	defer srv.Close()
+
+	s, err := scraper.NewScraper(scraper.WithRetry(3, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = s.ExtractText(srv.URL)
+	var networkErr *scraper.NetworkError
+	if !errors.As(err, &networkErr) || networkErr.Kind != scraper.NetworkTLS {
+		t.Fatalf("ExtractText() error = %v, want a TLS NetworkError", err)
+	}
+	if n := conns.Load(); n != 1 {
+		t.Errorf("server got %d connections, want 1", n)
+	}
+}
@@ -0,0 +1,320 @@
+package scraper
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Robots files larger than this are truncated, like Google does
+const maxRobotsBytes = 500 << 10
+
+const (
+	// How long a fetched robots.txt is trusted, the most RFC 9309 allows
+	robotsTTL = 24 * time.Hour
+	// How long a host whose robots.txt couldn't be fetched stays disallowed
+	// before it's tried again
+	robotsRetryTTL = time.Minute
+)
+
+// Parsed robots.txt files, keyed by scheme and host
+type robotsCache struct {
+	mu    sync.Mutex
+	hosts map[string]*robotsTxt
+}
+
+type robotsTxt struct {
+	groups []robotsGroup
+	// URLs from Sitemap lines, which apply whatever the user agent
+	sitemaps []string
+	// When the file must be fetched again
+	expires time.Time
+}
+
+// A run of user-agent lines and the rules that apply to them
+type robotsGroup struct {
+	agents []string
+	rules  []robotsRule
+	// Minimum time between requests, 0 if not set
+	crawlDelay time.Duration
+}
+
+type robotsRule struct {
+	allow   bool
+	pattern string
+}
+
+// Enables checking robots.txt before each fetch. Disallowed URLs fail with
+// ErrDisallowedByRobots. A Crawl-delay for the Scraper's user agent spaces
+// out requests to that host, unless WithRateLimit is already stricter. Each
+// host's file is kept for a day, or a minute if it couldn't be fetched.
+func WithRobotsTxt(enabled bool) Option {
+	return func(s *Scraper) {
+		if enabled {
+			s.robots = &robotsCache{hosts: make(map[string]*robotsTxt)}
+		} else {
+			s.robots = nil
+		}
+	}
+}
+
+// Checks whether req may be fetched according to its host's robots.txt,
+// fetching the file on first use and again once it expires
+func (s *Scraper) checkRobots(req *http.Request) error {
+	// Local files have no robots.txt to consult
+	if req.URL.Scheme == "file" {
+		return nil
+	}
+
+	key := req.URL.Scheme + "://" + req.URL.Host
+
+	s.robots.mu.Lock()
+	rt, ok := s.robots.hosts[key]
+	s.robots.mu.Unlock()
+
+	if !ok || time.Now().After(rt.expires) {
+		var err error
+		rt, err = s.fetchRobots(req.Context(), key, req.Header.Get("User-Agent"))
+		if err != nil {
+			return err
+		}
+
+		s.robots.mu.Lock()
+		s.robots.hosts[key] = rt
+		s.robots.mu.Unlock()
+
+		if delay := rt.crawlDelay(req.Header.Get("User-Agent")); delay > 0 {
+			s.limiters.slowDown(req.URL.Host, delay)
+		}
+	}
+
+	path := req.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	if req.URL.RawQuery != "" {
+		path += "?" + req.URL.RawQuery
+	}
+
+	if !rt.allowed(req.Header.Get("User-Agent"), path) {
+		return ErrDisallowedByRobots
+	}
+	return nil
+}
+
+// Follows RFC 9309: a missing robots.txt (4xx) allows everything, while a
+// server error or an unreachable host disallows everything. Those failures
+// expire after robotsRetryTTL, so a brief outage doesn't block the host for
+// a whole robotsTTL.
+func (s *Scraper) fetchRobots(ctx context.Context, origin, ua string) (*robotsTxt, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, origin+"/robots.txt", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", ua)
+
+	resp, err := s.do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		// Not a verdict on the host's robots.txt, so it mustn't be cached
+		if errors.Is(err, ErrCircuitOpen) {
+			return nil, err
+		}
+		return disallowAll(), nil
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= 500:
+		return disallowAll(), nil
+	case resp.StatusCode != http.StatusOK:
+		return &robotsTxt{expires: time.Now().Add(robotsTTL)}, nil
+	}
+
+	rt := parseRobots(io.LimitReader(resp.Body, maxRobotsBytes))
+	rt.expires = time.Now().Add(robotsTTL)
+	return rt, nil
+}
+
+func disallowAll() *robotsTxt {
+	return &robotsTxt{
+		groups: []robotsGroup{{
+			agents: []string{"*"},
+			rules:  []robotsRule{{allow: false, pattern: "/"}},
+		}},
+		expires: time.Now().Add(robotsRetryTTL),
+	}
+}
+
+func parseRobots(r io.Reader) *robotsTxt {
+	var rt robotsTxt
+	var cur *robotsGroup
+	inAgents := false
+
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		line := sc.Text()
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		key, val, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		val = strings.TrimSpace(val)
+
+		switch key {
+		case "user-agent":
+			// Consecutive user-agent lines share the rules that follow them
+			if !inAgents {
+				rt.groups = append(rt.groups, robotsGroup{})
+				cur = &rt.groups[len(rt.groups)-1]
+			}
+			cur.agents = append(cur.agents, strings.ToLower(val))
+			inAgents = true
+		case "allow", "disallow":
+			inAgents = false
+			// An empty disallow means allow everything; rules before any
+			// user-agent line belong to no group
+			if cur == nil || val == "" {
+				continue
+			}
+			cur.rules = append(cur.rules, robotsRule{allow: key == "allow", pattern: val})
+		case "crawl-delay":
+			inAgents = false
+			// Given in seconds, possibly fractional
+			secs, err := strconv.ParseFloat(val, 64)
+			if cur == nil || err != nil || secs <= 0 {
+				continue
+			}
+			cur.crawlDelay = time.Duration(secs * float64(time.Second))
+		case "sitemap":
+			// Belongs to no group, so doesn't end the user-agent lines
+			if val != "" {
+				rt.sitemaps = append(rt.sitemaps, val)
+			}
+		default:
+			inAgents = false
+		}
+	}
+	return &rt
+}
+
+// Reports whether ua may fetch path. The most specific (longest) matching
+// rule wins, with allow winning ties.
+func (rt *robotsTxt) allowed(ua, path string) bool {
+	rules, ok := rt.rulesFor(ua)
+	if !ok {
+		return true
+	}
+
+	best := -1
+	allow := true
+	for _, r := range rules {
+		if !matchRobotsPattern(r.pattern, path) {
+			continue
+		}
+		if n := len(r.pattern); n > best || (n == best && r.allow) {
+			best = n
+			allow = r.allow
+		}
+	}
+	return allow
+}
+
+// Collects the rules from every group naming ua's product token, falling
+// back to the "*" groups
+func (rt *robotsTxt) rulesFor(ua string) ([]robotsRule, bool) {
+	groups := rt.groupsFor(ua)
+	if len(groups) == 0 {
+		return nil, false
+	}
+
+	var rules []robotsRule
+	for _, g := range groups {
+		rules = append(rules, g.rules...)
+	}
+	return rules, true
+}
+
+// Returns the longest Crawl-delay of the groups that apply to ua, 0 if none
+// sets one
+func (rt *robotsTxt) crawlDelay(ua string) time.Duration {
+	var delay time.Duration
+	for _, g := range rt.groupsFor(ua) {
+		delay = max(delay, g.crawlDelay)
+	}
+	return delay
+}
+
+// Returns every group naming ua's product token, or if there are none the
+// "*" groups
+func (rt *robotsTxt) groupsFor(ua string) []*robotsGroup {
+	token, _, _ := strings.Cut(strings.ToLower(ua), "/")
+	token = strings.TrimSpace(token)
+
+	var named, wildcard []*robotsGroup
+	for i := range rt.groups {
+		g := &rt.groups[i]
+		for _, a := range g.agents {
+			switch {
+			case a == "*":
+				wildcard = append(wildcard, g)
+			case token != "" && a == token:
+				named = append(named, g)
+			default:
+				continue
+			}
+			break
+		}
+	}
+
+	if len(named) > 0 {
+		return named
+	}
+	return wildcard
+}
+
+// Matches a robots.txt path pattern, where * matches any run of characters
+// and a trailing $ anchors the end of the path
+func matchRobotsPattern(pattern, path string) bool {
+	if p, err := url.PathUnescape(pattern); err == nil {
+		pattern = p
+	}
+	if p, err := url.PathUnescape(path); err == nil {
+		path = p
+	}
+
+	anchored := strings.HasSuffix(pattern, "$")
+	pattern = strings.TrimSuffix(pattern, "$")
+
+	parts := strings.Split(pattern, "*")
+	if !strings.HasPrefix(path, parts[0]) {
+		return false
+	}
+	rest := path[len(parts[0]):]
+
+	for i, part := range parts[1:] {
+		last := i == len(parts)-2
+		if last && anchored {
+			return strings.HasSuffix(rest, part)
+		}
+		j := strings.Index(rest, part)
+		if j < 0 {
+			return false
+		}
+		rest = rest[j+len(part):]
+	}
+
+	return !anchored || rest == ""
+}
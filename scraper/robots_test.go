@@ -0,0 +1,145 @@
+package scraper
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRobotsAllowed(t *testing.T) {
+	const robots = "User-agent: *\nDisallow: /private\nAllow: /private/open\n\n" +
+		"User-agent: goscrape\nDisallow: /\nAllow: /*.html$\n"
+	rt := parseRobots(strings.NewReader(robots))
+	tests := []struct {
+		ua, path string
+		want     bool
+	}{
+		{"other", "/", true},
+		{"other", "/private/x", false},
+		{"other", "/private/open/x", true},
+		{"goscrape/1.0", "/private/open/x", false},
+		{"goscrape/1.0", "/page.html", true},
+		{"goscrape/1.0", "/page.html?x=1", false},
+	}
+	for _, tt := range tests {
+		if got := rt.allowed(tt.ua, tt.path); got != tt.want {
+			t.Errorf("allowed(%q, %q) = %v, want %v", tt.ua, tt.path, got, tt.want)
+		}
+	}
+}
+
+// What each kind of robots.txt response means for the host, and how long
+// it's kept
+func TestRobotsResponses(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		allowed bool
+		ttl     time.Duration
+	}{
+		{"found", http.StatusOK, "User-agent: *\nDisallow: /private\n", true, robotsTTL},
+		{"disallowed", http.StatusOK, "User-agent: *\nDisallow: /\n", false, robotsTTL},
+		{"missing", http.StatusNotFound, "", true, robotsTTL},
+		{"forbidden", http.StatusForbidden, "", true, robotsTTL},
+		{"server error", http.StatusServiceUnavailable, "", false, robotsRetryTTL},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/robots.txt" {
+					w.WriteHeader(tt.status)
+					w.Write([]byte(tt.body))
+					return
+				}
+				w.Header().Set("Content-Type", "text/html")
+				w.Write([]byte("<p>ok</p>"))
+			}))
+			defer srv.Close()
+
+			s, err := NewScraper(WithRobotsTxt(true))
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = s.ExtractText(srv.URL + "/page")
+			if allowed := !errors.Is(err, ErrDisallowedByRobots); allowed != tt.allowed {
+				t.Errorf("ExtractText() error = %v, want allowed %v", err, tt.allowed)
+			}
+			assertRobotsTTL(t, s, srv.URL, tt.ttl)
+		})
+	}
+}
+
+func TestRobotsUnreachable(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	u := srv.URL
+	srv.Close()
+
+	s, err := NewScraper(WithRobotsTxt(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.ExtractText(u + "/page"); !errors.Is(err, ErrDisallowedByRobots) {
+		t.Errorf("ExtractText() error = %v, want ErrDisallowedByRobots", err)
+	}
+	assertRobotsTTL(t, s, u, robotsRetryTTL)
+}
+
+// A server error only blocks the host until the verdict expires
+func TestRobotsRecovers(t *testing.T) {
+	var fetches atomic.Int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			if fetches.Add(1) == 1 {
+				http.Error(w, "busy", http.StatusServiceUnavailable)
+			}
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte("<p>ok</p>"))
+	}))
+	defer srv.Close()
+
+	s, err := NewScraper(WithRobotsTxt(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for range 2 {
+		if _, err := s.ExtractText(srv.URL + "/page"); !errors.Is(err, ErrDisallowedByRobots) {
+			t.Errorf("ExtractText() error = %v, want ErrDisallowedByRobots", err)
+		}
+	}
+	if n := fetches.Load(); n != 1 {
+		t.Errorf("robots.txt fetched %d times before expiring, want 1", n)
+	}
+
+	s.robots.mu.Lock()
+	for _, rt := range s.robots.hosts {
+		rt.expires = time.Now().Add(-time.Second)
+	}
+	s.robots.mu.Unlock()
+
+	if text, err := s.ExtractText(srv.URL + "/page"); err != nil || text != "ok" {
+		t.Errorf("ExtractText() = %q, %v, want %q", text, err, "ok")
+	}
+	if n := fetches.Load(); n != 2 {
+		t.Errorf("robots.txt fetched %d times, want 2", n)
+	}
+}
+
+func assertRobotsTTL(t *testing.T, s *Scraper, origin string, ttl time.Duration) {
+	t.Helper()
+	s.robots.mu.Lock()
+	rt, ok := s.robots.hosts[origin]
+	s.robots.mu.Unlock()
+	if !ok {
+		t.Fatalf("no robots.txt cached for %s", origin)
+	}
+	if left := time.Until(rt.expires); left <= ttl-time.Minute/2 || left > ttl {
+		t.Errorf("robots.txt expires in %v, want %v", left, ttl)
+	}
+}
@@ -0,0 +1,469 @@
+package scraper
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html"
+	"golang.org/x/time/rate"
+)
+
+const (
+	defaultTimeout   = 30 * time.Second
+	defaultUserAgent = "go-scrape/1.0"
+)
+
+// Fetches pages over HTTP and extracts their content.
+//
+// A Scraper is safe for concurrent use by multiple goroutines, and is meant
+// to be shared: its connections, cache, robots.txt files, rate limits and
+// cookies are kept across calls. Its settings can't change once created.
+type Scraper struct {
+	client *http.Client
+	// Set by WithHTTPClient, used as client instead of building one
+	customClient *http.Client
+
+	timeout time.Duration
+	header  http.Header
+	robots  *robotsCache
+
+	limiters *hostLimiters
+	delays   *hostDelays
+	cache    *textCache
+	flights  flightGroup
+	breakers *hostBreakers
+	jar      http.CookieJar
+	proxy    *url.URL
+
+	insecureSkipVerify  bool
+	rootCAs             *x509.CertPool
+	maxIdleConnsPerHost int
+	localFiles          bool
+
+	dialTimeout           time.Duration
+	tlsHandshakeTimeout   time.Duration
+	responseHeaderTimeout time.Duration
+
+	fetcher Fetcher
+
+	metaRefresh      bool
+	metaRefreshDelay time.Duration
+
+	maxAttempts  int
+	retryDelay   time.Duration
+	maxRedirects int
+	noRedirects  bool
+	maxBodySize  int64
+	minContent   int
+
+	// Statuses whose body is scraped, nil for just 200
+	acceptStatuses map[int]bool
+
+	extract *extractOptions
+
+	// Problems found while applying options, reported by NewScraper
+	optErrs []error
+}
+
+// Configures a Scraper created by NewScraper
+type Option func(*Scraper)
+
+// Sets the overall time limit for a single request, including reading the body
+func WithTimeout(d time.Duration) Option {
+	return func(s *Scraper) {
+		s.timeout = d
+	}
+}
+
+// Sets the User-Agent sent with every request
+func WithUserAgent(ua string) Option {
+	return WithHeader("User-Agent", ua)
+}
+
+// Asks sites for content in the given languages, as an Accept-Language
+// value such as "fr-FR,fr;q=0.9,en;q=0.5": language tags in order of
+// preference, optionally weighted by q from 1 down to 0
+func WithAcceptLanguage(lang string) Option {
+	return WithHeader("Accept-Language", lang)
+}
+
+// Sets a header sent with every request, replacing any previous value
+func WithHeader(key, value string) Option {
+	return func(s *Scraper) {
+		s.header.Set(key, value)
+	}
+}
+
+// Creates a Scraper, applying opts on top of the defaults. Fails if any of
+// the options were given invalid values.
+func NewScraper(opts ...Option) (*Scraper, error) {
+	s := &Scraper{
+		timeout:      defaultTimeout,
+		header:       http.Header{"User-Agent": {defaultUserAgent}},
+		maxAttempts:  1,
+		maxRedirects: defaultMaxRedirects,
+		maxBodySize:  defaultMaxBodySize,
+		extract:      newExtractOptions(),
+
+		maxIdleConnsPerHost: defaultMaxIdleConnsPerHost,
+		dialTimeout:         defaultDialTimeout,
+		tlsHandshakeTimeout: defaultTLSHandshakeTimeout,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if len(s.optErrs) > 0 {
+		return nil, errors.Join(s.optErrs...)
+	}
+
+	// Crawl-delay directives are enforced through the rate limiters, which
+	// don't limit anything until a host asks for it
+	if s.robots != nil && s.limiters == nil {
+		s.limiters = newHostLimiters(rate.Inf, 0)
+	}
+
+	if s.cache != nil {
+		s.cache.startJanitor()
+	}
+
+	s.client = s.customClient
+	if s.client == nil {
+		s.client = &http.Client{
+			Transport:     s.newTransport(),
+			Timeout:       s.timeout,
+			CheckRedirect: s.checkRedirect,
+			Jar:           s.jar,
+		}
+	}
+	return s, nil
+}
+
+// Releases what the Scraper holds on to in the background: it stops
+// sweeping its cache and closes its idle connections, unless they belong to
+// a client given with WithHTTPClient. The Scraper still works afterwards,
+// but its expired cache entries are no longer swept out. Safe to call more
+// than once.
+func (s *Scraper) Close() error {
+	if s.cache != nil {
+		s.cache.stopJanitor()
+	}
+	if s.customClient == nil {
+		s.client.CloseIdleConnections()
+	}
+	return nil
+}
+
+// Used by the package-level functions. Can't fail, as it has no options.
+// Local files stay off, so URLs from untrusted input can't read the disk.
+var defaultScraper, _ = NewScraper()
+
+// Extracts all visible text from the page at u
+func (s *Scraper) ExtractText(u string) (string, error) {
+	return s.ExtractTextContext(context.Background(), u)
+}
+
+// Same as ExtractText, but the fetch is aborted when ctx is done
+func (s *Scraper) ExtractTextContext(ctx context.Context, u string) (string, error) {
+	text, _, err := s.ExtractTextWithURL(ctx, u)
+	return text, err
+}
+
+// Extracts all visible text from the page at u, also returning the URL the
+// page was finally served from after following redirects. If the page is
+// past the extraction limits, the text so far is returned with ErrTruncated,
+// and if the download broke off, the text received is returned with
+// ErrIncomplete. Text shorter than WithMinContentLength allows is returned
+// with ErrInsufficientContent. Concurrent calls for the same URL share a
+// single fetch.
+func (s *Scraper) ExtractTextWithURL(ctx context.Context, u string) (text string, finalURL string, err error) {
+	text, finalURL, err = s.extractTextCached(ctx, u)
+	if err != nil && !isPartial(err) {
+		return "", "", err
+	}
+	if shortErr := s.checkContentLength(text); shortErr != nil {
+		return text, finalURL, shortErr
+	}
+	return text, finalURL, err
+}
+
+// Extracts the text of the page at u, from the cache if it has it
+func (s *Scraper) extractTextCached(ctx context.Context, u string) (text string, finalURL string, err error) {
+	// Per-call headers such as credentials can change the page, so a page
+	// is only reused for calls sending the same ones
+	key := requestKey(ctx, u)
+	var stale cacheEntry
+	revalidate := false
+	if s.cache != nil {
+		e, fresh, ok := s.cache.get(key)
+		if fresh {
+			return e.text, e.finalURL, nil
+		}
+
+		// An expired entry can still be reused if the page hasn't changed
+		if ok && !hasConditional(ctx) {
+			stale, revalidate = e, true
+			lastModified, _ := http.ParseTime(e.lastModified)
+			ctx = ContextWithConditional(ctx, e.etag, lastModified)
+		}
+	}
+
+	text, info, err := s.extractShared(ctx, u)
+	if revalidate && errors.Is(err, ErrNotModified) {
+		s.cache.set(key, stale.text, stale.finalURL, stale.etag, stale.lastModified)
+		return stale.text, stale.finalURL, nil
+	}
+	if err != nil && !isPartial(err) {
+		return "", "", err
+	}
+	if err == nil && s.cache != nil {
+		s.cache.set(key, text, info.URL, info.ETag, info.Header.Get("Last-Modified"))
+	}
+	return text, info.URL, err
+}
+
+// Extracts all visible text from the page at u, also returning details of
+// the response such as its caching headers. Always fetches the page, even
+// if the Scraper has a cache.
+func (s *Scraper) ExtractWithResponseInfo(ctx context.Context, u string) (text string, info ResponseInfo, err error) {
+	doc, resp, err := s.fetchPage(ctx, u)
+	if err != nil && !errors.Is(err, ErrIncomplete) {
+		return "", ResponseInfo{}, err
+	}
+
+	var b strings.Builder
+	if textErr := writeText(doc, &b, s.extract); textErr != nil {
+		err = errors.Join(err, textErr)
+	}
+	return b.String(), newResponseInfo(resp), err
+}
+
+// Downloads the page at u once, returning both its HTML, decoded to UTF-8,
+// and its visible text. If the download broke off, what was received is
+// returned with ErrIncomplete.
+func (s *Scraper) FetchAndExtract(ctx context.Context, u string) (rawHTML string, text string, err error) {
+	resp, err := s.fetch(ctx, u)
+	if err != nil {
+		return "", "", err
+	}
+	defer resp.Body.Close()
+
+	r := &partialReader{r: resp.Body}
+	body, err := io.ReadAll(r)
+	if ctx.Err() != nil {
+		return "", "", ctx.Err()
+	}
+	if err != nil {
+		return "", "", &ParseError{URL: resp.Request.URL.String(), Err: err}
+	}
+
+	text, err = extractTextFromReader(bytes.NewReader(body), s.extract)
+	if err != nil && !isPartial(err) {
+		return "", "", &ParseError{URL: resp.Request.URL.String(), Err: err}
+	}
+	if incomplete := r.incomplete(); incomplete != nil {
+		err = errors.Join(incomplete, err)
+	}
+	return string(body), text, err
+}
+
+// Fetches and parses the page at u. Also returns the URL the page was
+// finally served from, after redirects.
+func (s *Scraper) fetchDocument(ctx context.Context, u string) (*html.Node, *url.URL, error) {
+	doc, resp, err := s.fetchPage(ctx, u)
+	if err != nil {
+		return nil, nil, err
+	}
+	return doc, resp.Request.URL, nil
+}
+
+// Fetches and parses the page at u, following meta refreshes if enabled.
+// The returned response's body has already been read and closed. A page
+// whose download broke off is parsed as far as it got and returned with
+// ErrIncomplete.
+func (s *Scraper) fetchPage(ctx context.Context, u string) (*html.Node, *http.Response, error) {
+	visited := make(map[string]bool)
+	for hops := 0; ; hops++ {
+		doc, resp, err := s.parsePage(ctx, u)
+		if err != nil || !s.metaRefresh {
+			return doc, resp, err
+		}
+
+		target, ok := metaRefreshTarget(doc, resp.Request.URL, s.metaRefreshDelay)
+		if !ok {
+			return doc, resp, nil
+		}
+		// As with HTTP redirects, coming back to a page is only a loop if
+		// the same cookies go with it
+		visited[resp.Request.URL.String()+"\n"+resp.Request.Header.Get("Cookie")] = true
+		next, err := s.newRequest(ctx, http.MethodGet, target)
+		if err != nil {
+			return nil, nil, err
+		}
+		if visited[next.URL.String()+"\n"+s.outgoingCookies(next)] {
+			return nil, nil, fmt.Errorf("%w: %s", ErrRedirectLoop, target)
+		}
+		if hops >= s.maxRedirects {
+			return nil, nil, fmt.Errorf("%w: stopped after %d", ErrTooManyRedirects, s.maxRedirects)
+		}
+		u = target
+	}
+}
+
+// Fetches and parses the page at u. When the call is collecting stats, the
+// body is read in full before parsing so the fetch can be timed on its own.
+func (s *Scraper) parsePage(ctx context.Context, u string) (*html.Node, *http.Response, error) {
+	start := time.Now()
+	resp, err := s.fetch(ctx, u)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	body := &partialReader{r: resp.Body}
+	var r io.Reader = body
+	if rec := statsFrom(ctx); rec != nil {
+		b, err := io.ReadAll(body)
+		rec.addFetch(time.Since(start))
+		if err != nil && ctx.Err() == nil {
+			return nil, nil, &ParseError{URL: resp.Request.URL.String(), Err: err}
+		}
+		r = bytes.NewReader(b)
+	}
+	doc, err := html.Parse(r)
+
+	// A cancelled body read can surface as a parse error or a silently
+	// truncated document, so report the cancellation instead
+	if ctx.Err() != nil {
+		return nil, nil, ctx.Err()
+	}
+	if err != nil {
+		return nil, nil, &ParseError{URL: resp.Request.URL.String(), Err: err}
+	}
+	return doc, resp, body.incomplete()
+}
+
+// Fetches the HTML page at u, through the configured Fetcher if there is
+// one, with the body decoded to UTF-8. The caller must close the response
+// body.
+func (s *Scraper) fetch(ctx context.Context, u string) (*http.Response, error) {
+	if s.fetcher != nil {
+		return s.fetchWithFetcher(ctx, u)
+	}
+	return s.fetchHTTP(ctx, u)
+}
+
+// Issues a GET for an HTML page at u and returns the response if it is
+// usable, with the body decoded to UTF-8. The caller must close the
+// response body.
+func (s *Scraper) fetchHTTP(ctx context.Context, u string) (*http.Response, error) {
+	resp, err := s.get(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkContentType(resp.Header.Get("Content-Type")); err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+
+	body, warning := toUTF8(resp.Body, resp.Header.Get("Content-Type"))
+	addWarning(ctx, warning)
+	resp.Body = &decodedBody{Reader: body, body: resp.Body}
+	return resp, nil
+}
+
+// Issues a GET for u, whatever its content type, and returns the response
+// if it succeeded. The body has its Content-Encoding undone and is capped
+// at the maximum body size. The caller must close the response body.
+func (s *Scraper) get(ctx context.Context, u string) (*http.Response, error) {
+	req, err := s.newRequest(ctx, http.MethodGet, u)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.robots != nil {
+		if err := s.checkRobots(req); err != nil {
+			return nil, err
+		}
+	}
+
+	rec := statsFrom(ctx)
+	if rec != nil && req.Header.Get("Accept-Encoding") == "" {
+		// Asked for explicitly, the transport leaves a gzipped body as it
+		// came so its size can be counted, and decodeBody undoes it
+		req.Header.Set("Accept-Encoding", "gzip")
+	}
+
+	resp, err := s.do(req)
+	if err != nil {
+		return nil, networkError(u, err)
+	}
+
+	if resp.StatusCode == http.StatusNotModified {
+		resp.Body.Close()
+		return nil, ErrNotModified
+	}
+	if s.noRedirects && isRedirect(resp.StatusCode) {
+		resp.Body.Close()
+		return nil, &RedirectError{
+			URL:        resp.Request.URL.String(),
+			StatusCode: resp.StatusCode,
+			Location:   resp.Header.Get("Location"),
+		}
+	}
+	if !s.acceptsStatus(resp.StatusCode) {
+		resp.Body.Close()
+		return nil, &FetchError{URL: resp.Request.URL.String(), StatusCode: resp.StatusCode}
+	}
+
+	if s.maxBodySize > 0 && resp.ContentLength > s.maxBodySize {
+		resp.Body.Close()
+		return nil, ErrBodyTooLarge
+	}
+
+	if rec != nil {
+		resp.Body = &countingBody{ReadCloser: resp.Body, rec: rec}
+	}
+	if err := decodeBody(resp); err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+
+	if s.maxBodySize > 0 {
+		// Applied after decoding so compressed bodies can't expand past it
+		resp.Body = &decodedBody{
+			Reader: &limitedReader{r: resp.Body, n: s.maxBodySize},
+			body:   resp.Body,
+		}
+	}
+	return resp, nil
+}
+
+// Builds a request carrying the Scraper's headers merged with any
+// per-call headers attached to ctx
+func (s *Scraper) newRequest(ctx context.Context, method, u string) (*http.Request, error) {
+	if s.localFiles {
+		u = localFileURL(u)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header = s.header.Clone()
+	if h, ok := ctx.Value(headerKey{}).(http.Header); ok {
+		for k, v := range h {
+			req.Header[k] = v
+		}
+	}
+	return req, nil
+}
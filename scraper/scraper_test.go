@@ -0,0 +1,81 @@
+package scraper_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/charlescqian/go-scrape/scraper"
+	"golang.org/x/time/rate"
+)
+
+// Run with -race: goroutines sharing one Scraper go through its cache,
+// robots.txt cache, rate limiters, circuit breakers, cookie jar and shared
+// fetches all at once
+func TestScraperConcurrentUse(t *testing.T) {
+	const pages = 5
+	const goroutines = 50
+
+	var hits atomic.Int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			fmt.Fprint(w, "User-agent: *\nAllow: /\n")
+			return
+		}
+		hits.Add(1)
+		http.SetCookie(w, &http.Cookie{Name: "seen", Value: r.URL.Path})
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprintf(w, `<p>page %s</p><a href="/next">next</a>`, r.URL.Path)
+	}))
+	defer srv.Close()
+
+	s, err := scraper.NewScraper(
+		scraper.WithCache(time.Minute),
+		scraper.WithRateLimit(rate.Inf, 1),
+		scraper.WithRobotsTxt(true),
+		scraper.WithCookieJar(),
+		scraper.WithCircuitBreaker(3, time.Second),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	ctx := context.Background()
+	var wg sync.WaitGroup
+	for g := range goroutines {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range pages {
+				path := fmt.Sprintf("/p%d", (g+i)%pages)
+				text, err := s.ExtractTextContext(ctx, srv.URL+path)
+				if err != nil {
+					t.Errorf("ExtractText(%s): %v", path, err)
+					continue
+				}
+				if want := "page " + path + " next"; text != want {
+					t.Errorf("ExtractText(%s) = %q, want %q", path, text, want)
+				}
+				if _, err := s.ExtractLinks(ctx, srv.URL+path); err != nil {
+					t.Errorf("ExtractLinks(%s): %v", path, err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Every page is cached by now, so this mustn't reach the server
+	before := hits.Load()
+	if _, err := s.ExtractTextContext(ctx, srv.URL+"/p0"); err != nil {
+		t.Fatal(err)
+	}
+	if after := hits.Load(); after != before {
+		t.Errorf("cached page fetched again: %d requests, want %d", after, before)
+	}
+}
@@ -0,0 +1,79 @@
+// Package scrapetest provides fixtures for testing code that uses the
+// scraper package without reaching the network: an in-memory Fetcher for
+// any Scraper method, and a local HTTP server for the HTTP handling itself,
+// such as status codes and character encodings.
+package scrapetest
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/charlescqian/go-scrape/scraper"
+)
+
+// A Fetcher serving HTML from memory, keyed by absolute URL. Use it with
+// scraper.WithFetcher:
+//
+//	s, _ := scraper.NewScraper(scraper.WithFetcher(scrapetest.Pages{
+//		"https://example.com/": "<p>Hello</p>",
+//	}))
+//
+// URLs it doesn't have fail with a 404 FetchError.
+type Pages map[string]string
+
+func (p Pages) Fetch(ctx context.Context, u string) (io.ReadCloser, error) {
+	page, ok := p[u]
+	if !ok {
+		return nil, &scraper.FetchError{URL: u, StatusCode: http.StatusNotFound}
+	}
+	return io.NopCloser(strings.NewReader(page)), nil
+}
+
+// A response served by NewServer
+type Page struct {
+	// The raw body, in whatever encoding ContentType declares
+	Body string
+	// Defaults to "text/html; charset=utf-8"
+	ContentType string
+	// Defaults to 200
+	StatusCode int
+}
+
+// Starts a server answering GETs for each path in pages, such as
+// "/index.html", with its Page, and any other path with a 404. The caller
+// must Close it.
+func NewServer(pages map[string]Page) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, ok := pages[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+
+		contentType := page.ContentType
+		if contentType == "" {
+			contentType = "text/html; charset=utf-8"
+		}
+		w.Header().Set("Content-Type", contentType)
+		if page.StatusCode != 0 {
+			w.WriteHeader(page.StatusCode)
+		}
+		io.WriteString(w, page.Body)
+	}))
+}
+
+// Extracts the text of the page at u with s and fails tb unless it is want
+func AssertText(tb testing.TB, s *scraper.Scraper, u, want string) {
+	tb.Helper()
+	got, err := s.ExtractTextContext(context.Background(), u)
+	if err != nil {
+		tb.Fatalf("extracting text from %s: %v", u, err)
+	}
+	if got != want {
+		tb.Errorf("text of %s = %q, want %q", u, got, want)
+	}
+}
@@ -0,0 +1,182 @@
+package scraper
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// A run of visible text and where it came from in the page's HTML
+type Segment struct {
+	Text string `json:"text"`
+	// Byte offset and length of the source text, including any entities
+	// and whitespace that Text has normalized away. Offset is -1 in the
+	// rare case the parser built text that can't be traced to the source.
+	Offset int `json:"offset"`
+	Length int `json:"length"`
+}
+
+// Extracts the visible text of a page as segments, one per text node, with
+// their positions in the HTML. Offsets are into the page as decoded to
+// UTF-8.
+func ExtractSegments(u string) ([]Segment, error) {
+	return defaultScraper.ExtractSegments(context.Background(), u)
+}
+
+// Extracts the visible text of the page at u as segments with their
+// positions in the HTML
+func (s *Scraper) ExtractSegments(ctx context.Context, u string) ([]Segment, error) {
+	resp, err := s.fetch(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	segments, err := segmentsFromReader(resp.Body, s.extract)
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	if err != nil {
+		return nil, &ParseError{URL: resp.Request.URL.String(), Err: err}
+	}
+	return segments, nil
+}
+
+// Extracts the visible text of an HTML document read from r as segments
+// with their byte offsets in r, which must be UTF-8 encoded
+func ExtractSegmentsFromReader(r io.Reader) ([]Segment, error) {
+	return segmentsFromReader(r, defaultExtractOptions)
+}
+
+// Parses the document like the other extractors, so the same text is
+// visible, and traces each text node back to the tokens it came from, as
+// the parser doesn't keep track of where nodes came from
+func segmentsFromReader(r io.Reader, o *extractOptions) ([]Segment, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	src, err := textTokens(b)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := html.Parse(bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+
+	dedupe := o.newDeduper()
+	var segments []Segment
+
+	var traverse func(*html.Node)
+	traverse = func(n *html.Node) {
+		if o.isHidden(n) || o.isIgnorable(n) {
+			return
+		}
+		if n.Type == html.TextNode {
+			text := normalizeSpace(o.unescapeText(n.Data))
+			if text != "" && !dedupe.skip(text) {
+				offset, length := src.locate(n.Data)
+				segments = append(segments, Segment{Text: text, Offset: offset, Length: length})
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			traverse(c)
+		}
+	}
+	traverse(doc)
+
+	return segments, nil
+}
+
+// A run of text in the source, as the tokenizer saw it
+type sourceText struct {
+	raw    string
+	data   string
+	offset int
+}
+
+// The text tokens of a document in order, along with how far locate has
+// got through them
+type sourceTexts struct {
+	tokens []sourceText
+	// The token and byte of its data after the last one located
+	i, pos int
+}
+
+func textTokens(b []byte) (*sourceTexts, error) {
+	z := html.NewTokenizer(bytes.NewReader(b))
+	src := &sourceTexts{}
+	offset := 0
+	for {
+		tt := z.Next()
+		raw := z.Raw()
+		switch tt {
+		case html.ErrorToken:
+			if errors.Is(z.Err(), io.EOF) {
+				return src, nil
+			}
+			return nil, z.Err()
+		case html.TextToken:
+			src.tokens = append(src.tokens, sourceText{raw: string(raw), data: string(z.Text()), offset: offset})
+		}
+		offset += len(raw)
+	}
+}
+
+// Finds where the text of a node came from, searching on from the last
+// node found since the parser keeps text in document order. Text the
+// parser merged from several runs spans all of them, and text it moved,
+// such as stray text in a table, is searched for from the start. Returns
+// an offset of -1 if it can't be traced.
+func (s *sourceTexts) locate(data string) (offset, length int) {
+	for i := s.i; i < len(s.tokens); i++ {
+		t := s.tokens[i]
+		from := 0
+		if i == s.i {
+			from = s.pos
+		}
+		j := strings.Index(t.data[from:], data)
+		if j < 0 {
+			continue
+		}
+		s.i, s.pos = i, from+j+len(data)
+		if t.raw == t.data {
+			// Without entities the node's own bytes can be pinned down
+			return t.offset + from + j, len(data)
+		}
+		return t.offset, len(t.raw)
+	}
+
+	for i := s.i; i < len(s.tokens); i++ {
+		rest, ok := strings.CutPrefix(data, s.tokens[i].data)
+		if !ok || s.tokens[i].data == "" {
+			continue
+		}
+		end := i
+		for rest != "" && end+1 < len(s.tokens) && s.tokens[end+1].data != "" {
+			next, ok := strings.CutPrefix(rest, s.tokens[end+1].data)
+			if !ok {
+				break
+			}
+			rest = next
+			end++
+		}
+		if rest == "" {
+			s.i, s.pos = end, len(s.tokens[end].data)
+			last := s.tokens[end]
+			return s.tokens[i].offset, last.offset + len(last.raw) - s.tokens[i].offset
+		}
+	}
+
+	for _, t := range s.tokens[:s.i] {
+		if strings.Contains(t.data, data) {
+			return t.offset, len(t.raw)
+		}
+	}
+	return -1, 0
+}
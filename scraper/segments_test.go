@@ -0,0 +1,72 @@
+package scraper_test
+
+import (
+	"html"
+	"strings"
+	"testing"
+
+	"github.com/charlescqian/go-scrape/scraper"
+)
+
+// Segments must hold the same text as ExtractText, each pointing at where
+// it came from
+func TestExtractSegmentsMatchesText(t *testing.T) {
+	tests := []struct {
+		name string
+		html string
+	}{
+		{"entities", "<p>Fish &amp; chips</p>\n<p>plain  text</p>"},
+		{"implied head end", "<html><head><meta charset=utf-8><body><div>no head end</div>tail"},
+		{"hidden", `<p>shown</p><p hidden>secret</p><span style="display:none">gone</span>`},
+		{"scripts", "<p>one</p><script>var s = '<p>no</p>'</script><p>two</p>"},
+		{"stray text in a table", "<table><tr><td>cell</td></tr>stray</table>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			segments, err := scraper.ExtractSegmentsFromReader(strings.NewReader(tt.html))
+			if err != nil {
+				t.Fatal(err)
+			}
+			want, err := scraper.ExtractTextFromHTML(tt.html)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var texts []string
+			for _, seg := range segments {
+				texts = append(texts, seg.Text)
+				if seg.Offset < 0 || seg.Offset+seg.Length > len(tt.html) {
+					t.Errorf("segment %q at %d+%d is outside the source", seg.Text, seg.Offset, seg.Length)
+					continue
+				}
+				src := tt.html[seg.Offset : seg.Offset+seg.Length]
+				if !strings.Contains(strings.Join(strings.Fields(html.UnescapeString(src)), " "), seg.Text) {
+					t.Errorf("segment %q points at %q", seg.Text, src)
+				}
+			}
+			// Document order can differ from ExtractText's where the parser
+			// moved text, so compare the words regardless of order
+			got := strings.Fields(strings.Join(texts, " "))
+			if !sameWords(got, strings.Fields(want)) {
+				t.Errorf("segment text = %q, want the words of %q", got, want)
+			}
+		})
+	}
+}
+
+func sameWords(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	count := make(map[string]int)
+	for _, w := range a {
+		count[w]++
+	}
+	for _, w := range b {
+		count[w]--
+		if count[w] < 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,181 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/andybalholm/cascadia"
+	"golang.org/x/net/html"
+)
+
+// Extracts the visible text of the elements matching a CSS selector such as
+// "article" or ".post-content". Returns "" if nothing matches.
+func ExtractTextBySelector(u, selector string) (string, error) {
+	return defaultScraper.ExtractTextBySelector(context.Background(), u, selector)
+}
+
+// Extracts the visible text of the elements on the page at u that match
+// selector. As with ExtractText, the text found is returned with
+// ErrTruncated if the page is past the extraction limits, and with
+// ErrIncomplete if the download broke off.
+func (s *Scraper) ExtractTextBySelector(ctx context.Context, u, selector string) (string, error) {
+	sel, err := cascadia.Compile(selector)
+	if err != nil {
+		return "", err
+	}
+
+	doc, _, err := s.fetchPage(ctx, u)
+	if err != nil && !errors.Is(err, ErrIncomplete) {
+		return "", err
+	}
+
+	var parts []string
+	var textErr error
+	for _, n := range outermost(sel.MatchAll(doc)) {
+		var b strings.Builder
+		if nodeErr := writeText(n, &b, s.extract); nodeErr != nil && textErr == nil {
+			textErr = nodeErr
+		}
+		if b.Len() > 0 {
+			parts = append(parts, b.String())
+		}
+	}
+	if textErr != nil {
+		err = errors.Join(err, textErr)
+	}
+	return strings.Join(parts, " "), err
+}
+
+// Returns the inner HTML of the first element of a page matching a CSS
+// selector, for re-rendering part of a page. Returns "" if nothing matches.
+func ExtractHTMLBySelector(u, selector string) (string, error) {
+	return defaultScraper.ExtractHTMLBySelector(context.Background(), u, selector)
+}
+
+// Returns the inner HTML of the first element on the page at u that matches
+// selector, serialized from the parsed document, so it's well-formed even
+// if the page isn't
+func (s *Scraper) ExtractHTMLBySelector(ctx context.Context, u, selector string) (string, error) {
+	sel, err := cascadia.Compile(selector)
+	if err != nil {
+		return "", err
+	}
+
+	doc, _, err := s.fetchDocument(ctx, u)
+	if err != nil {
+		return "", err
+	}
+
+	n := sel.MatchFirst(doc)
+	if n == nil {
+		return "", nil
+	}
+	var b strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if err := html.Render(&b, c); err != nil {
+			return "", err
+		}
+	}
+	return b.String(), nil
+}
+
+// Returns the values of attr on the elements of a page matching a CSS
+// selector, e.g. every data-price on ".product-card". Matching elements
+// without the attribute are skipped.
+func ExtractAttributes(u, selector, attr string) ([]string, error) {
+	return defaultScraper.ExtractAttributes(context.Background(), u, selector, attr)
+}
+
+// Returns the values of attr on the elements of the page at u that match
+// selector
+func (s *Scraper) ExtractAttributes(ctx context.Context, u, selector, attr string) ([]string, error) {
+	sel, err := cascadia.Compile(selector)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, _, err := s.fetchDocument(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+
+	// The parser lowercases attribute names
+	attr = strings.ToLower(attr)
+
+	var values []string
+	for _, n := range sel.MatchAll(doc) {
+		for _, a := range n.Attr {
+			if a.Key == attr {
+				values = append(values, a.Val)
+				break
+			}
+		}
+	}
+	return values, nil
+}
+
+// Extracts all visible text from a page except that of the elements
+// matching any of selectors, e.g. ".cookie-banner" or "#comments"
+func ExtractTextExcluding(u string, selectors []string) (string, error) {
+	return defaultScraper.ExtractTextExcluding(context.Background(), u, selectors)
+}
+
+// Extracts all visible text from the page at u, leaving out the elements
+// that match any of selectors. As with ExtractText, the text found is
+// returned with ErrTruncated if the page is past the extraction limits, and
+// with ErrIncomplete if the download broke off.
+func (s *Scraper) ExtractTextExcluding(ctx context.Context, u string, selectors []string) (string, error) {
+	sels := make([]cascadia.Sel, 0, len(selectors))
+	for _, selector := range selectors {
+		sel, err := cascadia.Parse(selector)
+		if err != nil {
+			return "", err
+		}
+		sels = append(sels, sel)
+	}
+
+	doc, _, err := s.fetchPage(ctx, u)
+	if err != nil && !errors.Is(err, ErrIncomplete) {
+		return "", err
+	}
+
+	var matches []*html.Node
+	for _, sel := range sels {
+		matches = append(matches, cascadia.QueryAll(doc, sel)...)
+	}
+	for _, n := range outermost(matches) {
+		if n.Parent != nil {
+			n.Parent.RemoveChild(n)
+		}
+	}
+	var b strings.Builder
+	if textErr := writeText(doc, &b, s.extract); textErr != nil {
+		err = errors.Join(err, textErr)
+	}
+	return b.String(), err
+}
+
+// Drops nodes nested inside other nodes of the list, so that overlapping
+// matches don't contribute the same text twice
+func outermost(nodes []*html.Node) []*html.Node {
+	set := make(map[*html.Node]bool, len(nodes))
+	for _, n := range nodes {
+		set[n] = true
+	}
+
+	var out []*html.Node
+	for _, n := range nodes {
+		nested := false
+		for p := n.Parent; p != nil; p = p.Parent {
+			if set[p] {
+				nested = true
+				break
+			}
+		}
+		if !nested {
+			out = append(out, n)
+		}
+	}
+	return out
+}
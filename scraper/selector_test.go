@@ -0,0 +1,42 @@
+package scraper_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/charlescqian/go-scrape/scraper"
+	"github.com/charlescqian/go-scrape/scraper/scrapetest"
+)
+
+func TestExtractTextExcluding(t *testing.T) {
+	const page = `<p>one</p><div class="ad"><p>buy</p></div><p>two</p><div id="comments"><p>hi</p></div><p>three</p>`
+	tests := []struct {
+		name      string
+		opts      []scraper.Option
+		selectors []string
+		want      string
+		wantErr   error
+	}{
+		{"none", nil, nil, "one buy two hi three", nil},
+		{"several", nil, []string{".ad", "#comments"}, "one two three", nil},
+		{"nested matches", nil, []string{"div", "div p"}, "one two three", nil},
+		{"truncated", []scraper.Option{scraper.WithMaxNodes(8)}, []string{".ad"}, "one two", scraper.ErrTruncated},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]scraper.Option{scraper.WithFetcher(scrapetest.Pages{"https://example.com/": page})}, tt.opts...)
+			s, err := scraper.NewScraper(opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := s.ExtractTextExcluding(context.Background(), "https://example.com/", tt.selectors)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ExtractTextExcluding() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ExtractTextExcluding() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
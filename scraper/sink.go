@@ -0,0 +1,73 @@
+package scraper
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+)
+
+// Receives the pages of a crawl as they are scraped, to store them
+// somewhere instead of keeping them in memory
+type Sink interface {
+	// Called once per page, failed ones included. Calls are never
+	// concurrent. An error stops the crawl.
+	Write(CrawlResult) error
+}
+
+// Crawls like Crawl, handing each page to sink as soon as it has been
+// scraped, including pages that failed. Returns the first error from sink,
+// which stops the crawl.
+func CrawlToSink(startURL string, opts CrawlOptions, sink Sink) error {
+	return defaultScraper.CrawlToSink(context.Background(), startURL, opts, sink)
+}
+
+// Crawls like Crawl, handing each page to sink as soon as it has been
+// scraped, so a crawl of any size holds only its frontier in memory
+func (s *Scraper) CrawlToSink(ctx context.Context, startURL string, opts CrawlOptions, sink Sink) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var mu sync.Mutex
+	var sinkErr error
+	err := s.crawl(ctx, []string{startURL}, opts, nil, func(u string, p crawledPage) {
+		mu.Lock()
+		defer mu.Unlock()
+		if sinkErr != nil {
+			return
+		}
+		if sinkErr = sink.Write(CrawlResult{URL: u, Text: p.text, Err: p.err}); sinkErr != nil {
+			cancel()
+		}
+	})
+
+	mu.Lock()
+	defer mu.Unlock()
+	if sinkErr != nil {
+		return sinkErr
+	}
+	return err
+}
+
+// A Sink writing each page to w as a line of JSON:
+// {"url": ..., "text": ..., "error": ...}, with error only on failed pages
+type JSONLinesSink struct {
+	enc *json.Encoder
+}
+
+// Creates a JSONLinesSink writing to w
+func NewJSONLinesSink(w io.Writer) *JSONLinesSink {
+	return &JSONLinesSink{enc: json.NewEncoder(w)}
+}
+
+func (s *JSONLinesSink) Write(r CrawlResult) error {
+	line := struct {
+		URL   string `json:"url"`
+		Text  string `json:"text"`
+		Error string `json:"error,omitempty"`
+	}{URL: r.URL, Text: r.Text}
+	if r.Err != nil {
+		line.Error = r.Err.Error()
+	}
+	return s.enc.Encode(line)
+}
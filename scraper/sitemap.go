@@ -0,0 +1,174 @@
+package scraper
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/xml"
+	"errors"
+	"io"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html/charset"
+)
+
+// How many levels of sitemap index files are followed
+const maxSitemapDepth = 3
+
+// Either a <urlset> of pages or a <sitemapindex> pointing at more sitemaps
+type sitemapFile struct {
+	URLs []struct {
+		Loc string `xml:"loc"`
+	} `xml:"url"`
+	Sitemaps []struct {
+		Loc string `xml:"loc"`
+	} `xml:"sitemap"`
+}
+
+// Lists the page URLs in a site's sitemaps: those its robots.txt lists in
+// Sitemap lines, or else its /sitemap.xml. Sitemap index files are followed
+// to the sitemaps they reference. Gzipped sitemaps are supported.
+func DiscoverFromSitemap(siteURL string) ([]string, error) {
+	return defaultScraper.DiscoverFromSitemap(context.Background(), siteURL)
+}
+
+// Lists the page URLs in the sitemaps of siteURL's host, as found through
+// its robots.txt or at /sitemap.xml. Fails only if none of them could be
+// read.
+func (s *Scraper) DiscoverFromSitemap(ctx context.Context, siteURL string) ([]string, error) {
+	roots, err := s.sitemapRoots(ctx, siteURL)
+	if err != nil {
+		return nil, err
+	}
+
+	seenSitemaps := make(map[string]bool)
+	seenURLs := make(map[string]bool)
+	var urls []string
+
+	var walk func(u string, depth int) error
+	walk = func(u string, depth int) error {
+		seenSitemaps[u] = true
+
+		sm, err := s.fetchSitemap(ctx, u)
+		if err != nil {
+			return err
+		}
+
+		for _, e := range sm.URLs {
+			loc := strings.TrimSpace(e.Loc)
+			if loc != "" && !seenURLs[loc] {
+				seenURLs[loc] = true
+				urls = append(urls, loc)
+			}
+		}
+
+		if depth >= maxSitemapDepth {
+			return nil
+		}
+		for _, e := range sm.Sitemaps {
+			loc := strings.TrimSpace(e.Loc)
+			if loc == "" || seenSitemaps[loc] {
+				continue
+			}
+			// One broken child sitemap shouldn't hide the rest
+			if err := walk(loc, depth+1); err != nil && ctx.Err() != nil {
+				return ctx.Err()
+			}
+		}
+		return nil
+	}
+
+	var firstErr error
+	read := false
+	for _, root := range roots {
+		if seenSitemaps[root] {
+			continue
+		}
+		err := walk(root, 0)
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if err == nil {
+			read = true
+		} else if firstErr == nil {
+			firstErr = err
+		}
+	}
+	if !read {
+		return nil, firstErr
+	}
+	return urls, nil
+}
+
+// Finds where a site's sitemaps start: the Sitemap lines of its robots.txt,
+// or /sitemap.xml if it lists none
+func (s *Scraper) sitemapRoots(ctx context.Context, siteURL string) ([]string, error) {
+	site, err := url.Parse(siteURL)
+	if err != nil {
+		return nil, err
+	}
+	origin := &url.URL{Scheme: site.Scheme, Host: site.Host}
+
+	var roots []string
+	rt, err := s.fetchRobots(ctx, origin.String(), s.header.Get("User-Agent"))
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	if err == nil {
+		for _, loc := range rt.sitemaps {
+			if u, ok := resolveLink(origin, loc); ok {
+				roots = append(roots, u)
+			}
+		}
+	}
+	if len(roots) == 0 {
+		roots = append(roots, origin.JoinPath("/sitemap.xml").String())
+	}
+	return roots, nil
+}
+
+func (s *Scraper) fetchSitemap(ctx context.Context, u string) (*sitemapFile, error) {
+	resp, err := s.get(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	br := bufio.NewReader(resp.Body)
+	var r io.Reader = br
+	if isGzip(br) {
+		// sitemap.xml.gz files are usually served as plain gzip data rather
+		// than with a Content-Encoding
+		zr, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, &ParseError{URL: u, Err: err}
+		}
+		defer zr.Close()
+		r = zr
+		if s.maxBodySize > 0 {
+			// The body limit only saw the compressed size
+			r = &limitedReader{r: zr, n: s.maxBodySize}
+		}
+	}
+
+	dec := xml.NewDecoder(r)
+	dec.CharsetReader = charset.NewReaderLabel
+
+	var sm sitemapFile
+	if err := dec.Decode(&sm); err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if errors.Is(err, ErrBodyTooLarge) {
+			return nil, ErrBodyTooLarge
+		}
+		return nil, &ParseError{URL: u, Err: err}
+	}
+	return &sm, nil
+}
+
+func isGzip(br *bufio.Reader) bool {
+	b, err := br.Peek(2)
+	return err == nil && b[0] == 0x1f && b[1] == 0x8b
+}
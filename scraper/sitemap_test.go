@@ -0,0 +1,96 @@
+package scraper_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/charlescqian/go-scrape/scraper"
+)
+
+func gzipped(t *testing.T, s string) []byte {
+	t.Helper()
+	var b bytes.Buffer
+	zw := gzip.NewWriter(&b)
+	if _, err := zw.Write([]byte(s)); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return b.Bytes()
+}
+
+func TestDiscoverFromSitemapRobots(t *testing.T) {
+	mux := http.NewServeMux()
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	mux.HandleFunc("/robots.txt", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("User-agent: *\nSitemap: " + srv.URL + "/maps/pages.xml.gz\nDisallow: /private\nSitemap: /maps/posts.xml\n"))
+	})
+	mux.HandleFunc("/maps/pages.xml.gz", func(w http.ResponseWriter, r *http.Request) {
+		w.Write(gzipped(t, `<urlset><url><loc>https://example.com/a</loc></url></urlset>`))
+	})
+	mux.HandleFunc("/maps/posts.xml", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<urlset><url><loc>https://example.com/b</loc></url><url><loc>https://example.com/a</loc></url></urlset>`))
+	})
+	mux.HandleFunc("/sitemap.xml", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("fetched /sitemap.xml although robots.txt lists sitemaps")
+	})
+
+	s, err := scraper.NewScraper()
+	if err != nil {
+		t.Fatal(err)
+	}
+	urls, err := s.DiscoverFromSitemap(context.Background(), srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"https://example.com/a", "https://example.com/b"}; !slices.Equal(urls, want) {
+		t.Errorf("DiscoverFromSitemap() = %q, want %q", urls, want)
+	}
+}
+
+func TestDiscoverFromSitemapDefault(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/sitemap.xml", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<urlset><url><loc>https://example.com/a</loc></url></urlset>`))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	urls, err := scraper.DiscoverFromSitemap(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"https://example.com/a"}; !slices.Equal(urls, want) {
+		t.Errorf("DiscoverFromSitemap() = %q, want %q", urls, want)
+	}
+}
+
+func TestDiscoverFromSitemapGzipLimit(t *testing.T) {
+	// Compresses to a few kilobytes, well under the limit, but expands far
+	// past it
+	bomb := gzipped(t, "<urlset>"+strings.Repeat(" ", 4<<20)+"</urlset>")
+	mux := http.NewServeMux()
+	mux.HandleFunc("/sitemap.xml", func(w http.ResponseWriter, r *http.Request) {
+		w.Write(bomb)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	s, err := scraper.NewScraper(scraper.WithMaxBodySize(1 << 20))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.DiscoverFromSitemap(context.Background(), srv.URL); !errors.Is(err, scraper.ErrBodyTooLarge) {
+		t.Errorf("DiscoverFromSitemap() error = %v, want ErrBodyTooLarge", err)
+	}
+}
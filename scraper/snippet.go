@@ -0,0 +1,86 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Stops writeText once a wordLimitWriter has all the words it wants
+var errWordLimit = errors.New("word limit reached")
+
+// Extracts the visible text of a page up to its first maxWords words, for
+// previews and snippets. A maxWords of 0 or less means no limit.
+func ExtractTextLimited(u string, maxWords int) (string, error) {
+	return defaultScraper.ExtractTextLimited(context.Background(), u, maxWords)
+}
+
+// Extracts the visible text of the page at u up to its first maxWords
+// words, stopping the traversal as soon as it has them. Like
+// ExtractTextWithURL, a page past the extraction limits or whose download
+// broke off returns the text so far with ErrTruncated or ErrIncomplete,
+// the latter even if the words were all found before the break.
+func (s *Scraper) ExtractTextLimited(ctx context.Context, u string, maxWords int) (string, error) {
+	if maxWords <= 0 {
+		return s.ExtractTextContext(ctx, u)
+	}
+
+	doc, _, err := s.fetchPage(ctx, u)
+	if err != nil && !errors.Is(err, ErrIncomplete) {
+		return "", err
+	}
+
+	w := &wordLimitWriter{max: maxWords}
+	textErr := writeText(doc, w, s.extract)
+	if errors.Is(textErr, errWordLimit) {
+		// Whatever came after the last word kept is beside the point, but a
+		// broken download still means the page wasn't all there
+		return w.String(), err
+	}
+	if textErr != nil {
+		err = errors.Join(err, textErr)
+	}
+	return w.String(), err
+}
+
+// Collects text until it has max words, then fails the write that would
+// start the next one with errWordLimit. A word is a run of non-space
+// characters, even if it arrives over several writes.
+type wordLimitWriter struct {
+	b      strings.Builder
+	max    int
+	words  int
+	inWord bool
+	// Length of b up to the end of the last word, so trailing separators
+	// can be dropped
+	end int
+}
+
+func (w *wordLimitWriter) Write(p []byte) (int, error) {
+	for i := 0; i < len(p); {
+		r, size := utf8.DecodeRune(p[i:])
+		if unicode.IsSpace(r) {
+			w.inWord = false
+		} else {
+			if !w.inWord {
+				w.words++
+				if w.words > w.max {
+					w.b.Write(p[:i])
+					return i, errWordLimit
+				}
+			}
+			w.inWord = true
+			w.end = w.b.Len() + i + size
+		}
+		i += size
+	}
+	w.b.Write(p)
+	return len(p), nil
+}
+
+// Returns the text collected, without anything trailing the last word
+func (w *wordLimitWriter) String() string {
+	return w.b.String()[:w.end]
+}
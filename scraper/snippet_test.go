@@ -0,0 +1,36 @@
+package scraper_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/charlescqian/go-scrape/scraper"
+)
+
+func TestExtractTextLimitedIncomplete(t *testing.T) {
+	// Promises more body than it sends, so the download breaks off
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Header().Set("Content-Length", "1000")
+		w.Write([]byte("<p>one two three four"))
+	}))
+	defer srv.Close()
+
+	s, err := scraper.NewScraper()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, limit := range []int{2, 10} {
+		text, err := s.ExtractTextLimited(context.Background(), srv.URL, limit)
+		if !errors.Is(err, scraper.ErrIncomplete) {
+			t.Errorf("ExtractTextLimited(%d) error = %v, want ErrIncomplete", limit, err)
+		}
+		want := map[int]string{2: "one two", 10: "one two three four"}[limit]
+		if text != want {
+			t.Errorf("ExtractTextLimited(%d) = %q, want %q", limit, text, want)
+		}
+	}
+}
@@ -0,0 +1,22 @@
+package scraper
+
+import "strings"
+
+// Average adult silent reading speed used for reading time estimates
+const wordsPerMinute = 200
+
+// Size measures of a piece of extracted text
+type TextStats struct {
+	WordCount               int     `json:"word_count"`
+	EstimatedReadingMinutes float64 `json:"estimated_reading_minutes"`
+}
+
+// Counts the words in text (separated by any amount of whitespace) and
+// estimates how long it takes to read
+func Stats(text string) TextStats {
+	words := len(strings.Fields(text))
+	return TextStats{
+		WordCount:               words,
+		EstimatedReadingMinutes: float64(words) / wordsPerMinute,
+	}
+}
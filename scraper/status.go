@@ -0,0 +1,34 @@
+package scraper
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// Sets the response statuses whose body is scraped, such as 203 or 206 from
+// servers that don't answer with a plain 200. Any other status fails the
+// fetch with a FetchError. Only 200 is accepted by default, and giving no
+// codes restores that.
+func WithAcceptStatuses(codes ...int) Option {
+	return func(s *Scraper) {
+		s.acceptStatuses = nil
+		for _, code := range codes {
+			if code < 100 || code > 599 {
+				s.optErrs = append(s.optErrs, fmt.Errorf("invalid HTTP status code %d", code))
+				continue
+			}
+			if s.acceptStatuses == nil {
+				s.acceptStatuses = make(map[int]bool)
+			}
+			s.acceptStatuses[code] = true
+		}
+	}
+}
+
+// Reports whether a response with status should be scraped
+func (s *Scraper) acceptsStatus(status int) bool {
+	if s.acceptStatuses == nil {
+		return status == http.StatusOK
+	}
+	return s.acceptStatuses[status]
+}
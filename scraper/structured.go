@@ -0,0 +1,147 @@
+package scraper
+
+import (
+	"context"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// Elements that start and end a paragraph in structured output
+var blockElements = map[string]bool{
+	"address": true, "article": true, "aside": true, "blockquote": true,
+	"dd": true, "div": true, "dl": true, "dt": true, "fieldset": true,
+	"figcaption": true, "figure": true, "footer": true, "form": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+	"header": true, "hr": true, "li": true, "main": true, "nav": true,
+	"ol": true, "p": true, "pre": true, "section": true, "table": true,
+	"tr": true, "ul": true,
+}
+
+// Extracts visible text from an HTML page, keeping block structure:
+// paragraphs are separated by blank lines and <br> becomes a line break
+func ExtractStructuredText(u string) (string, error) {
+	return defaultScraper.ExtractStructuredText(context.Background(), u)
+}
+
+// Extracts visible text from the page at u, keeping block structure
+func (s *Scraper) ExtractStructuredText(ctx context.Context, u string) (string, error) {
+	doc, _, err := s.fetchDocument(ctx, u)
+	if err != nil {
+		return "", err
+	}
+	return strings.Join(paragraphsFromHTML(doc, s.extract), "\n\n"), nil
+}
+
+// Extracts the visible text of an HTML page as a list of paragraphs, split
+// at block-level elements
+func ExtractParagraphs(u string) ([]string, error) {
+	return defaultScraper.ExtractParagraphs(context.Background(), u)
+}
+
+// Extracts the visible text of the page at u as a list of paragraphs
+func (s *Scraper) ExtractParagraphs(ctx context.Context, u string) ([]string, error) {
+	doc, _, err := s.fetchDocument(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+	return paragraphsFromHTML(doc, s.extract), nil
+}
+
+// Splits the visible text of doc into paragraphs at block-level elements.
+// Lines within a paragraph are separated by "\n".
+func paragraphsFromHTML(doc *html.Node, o *extractOptions) []string {
+	var paras, lines, words []string
+	// Marker for the next line, when it starts a list item
+	var prefix string
+	// Open lists, as for listItemPrefix, when marking list items
+	var lists []int
+	dedupe := o.newDeduper()
+
+	flushLine := func() {
+		if len(words) > 0 {
+			lines = append(lines, prefix+strings.Join(words, " "))
+			words = nil
+			prefix = ""
+		}
+	}
+	flushPara := func() {
+		flushLine()
+		if len(lines) > 0 {
+			paras = append(paras, strings.Join(lines, "\n"))
+			lines = nil
+		}
+	}
+
+	lim := o.newWalkLimits()
+	var traverse func(*html.Node)
+	traverse = func(n *html.Node) {
+		if !lim.enter() {
+			return
+		}
+		defer lim.leave()
+		if o.isHidden(n) || o.isIgnorable(n) {
+			return
+		}
+
+		switch {
+		case n.Type == html.TextNode:
+			text := normalizeSpace(n.Data)
+			if len(text) > 0 && !dedupe.skip(text) {
+				words = append(words, text)
+			}
+		case isElement(n, "br"):
+			flushLine()
+		}
+
+		block := n.Type == html.ElementNode && blockElements[n.Data]
+		list := o.listMarkers && (isElement(n, "ul") || isElement(n, "ol"))
+		item := o.listMarkers && isElement(n, "li") && len(lists) > 0
+
+		// Within a list, blocks only break the line, keeping the list
+		// together as one paragraph
+		switch {
+		case list:
+			if len(lists) == 0 {
+				flushPara()
+			} else {
+				flushLine()
+			}
+			if n.Data == "ol" {
+				lists = append(lists, 0)
+			} else {
+				lists = append(lists, -1)
+			}
+		case item:
+			flushLine()
+			prefix = listItemPrefix(lists)
+		case block && len(lists) > 0:
+			flushLine()
+		case block:
+			flushPara()
+		}
+
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			traverse(c)
+		}
+
+		switch {
+		case list:
+			flushLine()
+			prefix = ""
+			lists = lists[:len(lists)-1]
+			if len(lists) == 0 {
+				flushPara()
+			}
+		case block && len(lists) > 0:
+			flushLine()
+		case block:
+			flushPara()
+		}
+	}
+
+	traverse(doc)
+	flushPara()
+
+	return paras
+}
@@ -0,0 +1,150 @@
+package scraper
+
+import (
+	"context"
+	"strconv"
+
+	"golang.org/x/net/html"
+)
+
+// Upper bounds on spans from the HTML spec, so a hostile page can't make us
+// allocate huge rows
+const (
+	maxColspan = 1000
+	maxRowspan = 65534
+)
+
+// Extracts every <table> on a page as rows of cell text. Cells spanning
+// several columns or rows are repeated in each position they cover.
+func ExtractTables(u string) ([][][]string, error) {
+	return defaultScraper.ExtractTables(context.Background(), u)
+}
+
+// Extracts every <table> on the page at u as rows of cell text
+func (s *Scraper) ExtractTables(ctx context.Context, u string) ([][][]string, error) {
+	doc, _, err := s.fetchDocument(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+	return tablesFromHTML(doc, s.extract), nil
+}
+
+func tablesFromHTML(doc *html.Node, o *extractOptions) [][][]string {
+	var tables [][][]string
+
+	lim := o.newWalkLimits()
+	var traverse func(*html.Node)
+	traverse = func(n *html.Node) {
+		if !lim.enter() {
+			return
+		}
+		defer lim.leave()
+		if isElement(n, "table") {
+			tables = append(tables, tableRows(n, o))
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			traverse(c)
+		}
+	}
+
+	traverse(doc)
+
+	return tables
+}
+
+// A cell value carried down into the rows below by rowspan
+type spanCell struct {
+	text string
+	rows int
+}
+
+func tableRows(table *html.Node, o *extractOptions) [][]string {
+	var rows [][]string
+	pending := make(map[int]*spanCell)
+
+	for _, tr := range rowsOf(table) {
+		var row []string
+		col := 0
+
+		// Fills in columns still covered by a rowspan from above
+		fillSpans := func() {
+			for p := pending[col]; p != nil; p = pending[col] {
+				row = append(row, p.text)
+				if p.rows--; p.rows == 0 {
+					delete(pending, col)
+				}
+				col++
+			}
+		}
+
+		for c := tr.FirstChild; c != nil; c = c.NextSibling {
+			if !isElement(c, "td") && !isElement(c, "th") {
+				continue
+			}
+			fillSpans()
+
+			text := textFromNode(c, o)
+			colspan := spanAttr(c, "colspan", maxColspan)
+			rowspan := spanAttr(c, "rowspan", maxRowspan)
+			for range colspan {
+				row = append(row, text)
+				if rowspan > 1 {
+					pending[col] = &spanCell{text: text, rows: rowspan - 1}
+				}
+				col++
+			}
+		}
+
+		// Rowspans reaching past the last cell of this row
+		for len(pending) > 0 {
+			if pending[col] == nil {
+				if col > maxPendingCol(pending) {
+					break
+				}
+				row = append(row, "")
+				col++
+				continue
+			}
+			fillSpans()
+		}
+
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// Collects the <tr> elements belonging to table, including those inside
+// <thead>, <tbody> and <tfoot> but not those of nested tables
+func rowsOf(table *html.Node) []*html.Node {
+	var rows []*html.Node
+	for c := table.FirstChild; c != nil; c = c.NextSibling {
+		switch {
+		case isElement(c, "tr"):
+			rows = append(rows, c)
+		case isElement(c, "thead"), isElement(c, "tbody"), isElement(c, "tfoot"):
+			for r := c.FirstChild; r != nil; r = r.NextSibling {
+				if isElement(r, "tr") {
+					rows = append(rows, r)
+				}
+			}
+		}
+	}
+	return rows
+}
+
+func maxPendingCol(pending map[int]*spanCell) int {
+	m := -1
+	for col := range pending {
+		m = max(m, col)
+	}
+	return m
+}
+
+// Reads a colspan or rowspan attribute, defaulting to 1
+func spanAttr(n *html.Node, key string, limit int) int {
+	v, err := strconv.Atoi(getAttr(n, key))
+	if err != nil || v < 1 {
+		return 1
+	}
+	return min(v, limit)
+}
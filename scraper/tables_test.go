@@ -0,0 +1,69 @@
+package scraper_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/charlescqian/go-scrape/scraper"
+	"github.com/charlescqian/go-scrape/scraper/scrapetest"
+)
+
+func TestExtractTables(t *testing.T) {
+	type table = [][]string
+	tests := []struct {
+		name string
+		html string
+		want []table
+	}{
+		{"none", "<p>no tables</p>", nil},
+		{
+			"sections",
+			"<table><thead><tr><th>a</th><th>b</th></tr></thead><tbody><tr><td>1</td><td>2</td></tr></tbody><tfoot><tr><td>x</td><td>y</td></tr></tfoot></table>",
+			[]table{{{"a", "b"}, {"1", "2"}, {"x", "y"}}},
+		},
+		{
+			"colspan",
+			`<table><tr><td colspan="2">wide</td><td>c</td></tr><tr><td>1</td><td>2</td><td>3</td></tr></table>`,
+			[]table{{{"wide", "wide", "c"}, {"1", "2", "3"}}},
+		},
+		{
+			"rowspan",
+			`<table><tr><td rowspan="2">tall</td><td>b</td></tr><tr><td>2</td></tr></table>`,
+			[]table{{{"tall", "b"}, {"tall", "2"}}},
+		},
+		{
+			"rowspan past the last cell",
+			`<table><tr><td>a</td><td rowspan="2">tall</td></tr><tr><td>b</td></tr><tr></tr></table>`,
+			[]table{{{"a", "tall"}, {"b", "tall"}, nil}},
+		},
+		{
+			"bad spans",
+			`<table><tr><td colspan="0">a</td><td colspan="x">b</td></tr></table>`,
+			[]table{{{"a", "b"}}},
+		},
+		{
+			"nested",
+			"<table><tr><td>outer<table><tr><td>inner</td></tr></table></td></tr></table>",
+			[]table{{{"outer inner"}}, {{"inner"}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := scraper.NewScraper(scraper.WithFetcher(scrapetest.Pages{"https://example.com/": tt.html}))
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := s.ExtractTables(context.Background(), "https://example.com/")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) == 0 && len(tt.want) == 0 {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ExtractTables() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,64 +1,270 @@
 package scraper
 
 import (
-	"errors"
+	"bytes"
+	"context"
 	"io"
-	"net/http"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	"golang.org/x/net/html"
 )
 
 // Extracts all visible text from an HTML page
 func ExtractTextFromURL(u string) (string, error) {
-	resp, err := http.Get(u)
-	if err != nil {
-		return "", err
-	}
-	defer resp.Body.Close()
+	return defaultScraper.ExtractText(u)
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return "", errors.New("failed to fetch page")
-	}
+// Same as ExtractTextFromURL, but the fetch is aborted when ctx is done
+func ExtractTextFromURLContext(ctx context.Context, u string) (string, error) {
+	return defaultScraper.ExtractTextContext(ctx, u)
+}
+
+// Extracts all visible text from an HTML page, also returning the URL the
+// page was finally served from after following redirects
+func ExtractTextWithURL(u string) (text string, finalURL string, err error) {
+	return defaultScraper.ExtractTextWithURL(context.Background(), u)
+}
 
-	// Fallback to manual DOM traversal
-	return extractTextFromHTML(resp.Body)
+// Downloads a page once, returning both its HTML, decoded to UTF-8, and its
+// visible text
+func FetchAndExtract(u string) (rawHTML string, text string, err error) {
+	return defaultScraper.FetchAndExtract(context.Background(), u)
 }
 
-func extractTextFromHTML(r io.Reader) (string, error) {
+// Extracts all visible text from an HTML document read from r, which must
+// be UTF-8 encoded. Documents nested more than 512 elements deep return the
+// text outside the excess nesting with ErrTruncated.
+func ExtractTextFromReader(r io.Reader) (string, error) {
+	return extractTextFromReader(r, defaultExtractOptions)
+}
+
+// Extracts all visible text from an HTML document held in a string
+func ExtractTextFromHTML(htmlText string) (string, error) {
+	return ExtractTextFromReader(strings.NewReader(htmlText))
+}
+
+// Extracts all visible text from an HTML document held in b, which must be
+// UTF-8 encoded
+func ExtractTextFromBytes(b []byte) (string, error) {
+	return ExtractTextFromReader(bytes.NewReader(b))
+}
+
+func extractTextFromReader(r io.Reader, o *extractOptions) (string, error) {
 	doc, err := html.Parse(r)
 	if err != nil {
 		return "", err
 	}
+	var b strings.Builder
+	err = writeText(doc, &b, o)
+	return b.String(), err
+}
+
+// Writes the visible text of an HTML document read from r to w as the
+// document is traversed, instead of collecting it in memory first
+func ExtractTextTo(r io.Reader, w io.Writer) error {
+	doc, err := html.Parse(r)
+	if err != nil {
+		return err
+	}
+	return writeText(doc, w, defaultExtractOptions)
+}
+
+// Elements that flow within a line of text. With WithJoinInline, any other
+// element marks a word boundary, so its text isn't glued to its neighbours'.
+var inlineElements = map[string]bool{
+	"a": true, "abbr": true, "b": true, "bdi": true, "bdo": true,
+	"cite": true, "code": true, "data": true, "del": true, "dfn": true,
+	"em": true, "font": true, "i": true, "ins": true, "kbd": true,
+	"mark": true, "q": true, "s": true, "samp": true, "small": true,
+	"span": true, "strong": true, "sub": true, "sup": true, "time": true,
+	"u": true, "var": true, "wbr": true,
+}
 
+// Collects the visible text under n, separating words with spaces
+func textFromNode(n *html.Node, o *extractOptions) string {
 	var b strings.Builder
+	writeText(n, &b, o)
+	return b.String()
+}
 
-	var traverse func(*html.Node)
-	traverse = func(n *html.Node) {
-		if n.Type == html.TextNode && !isIgnorable(n.Parent) {
-			text := strings.TrimSpace(n.Data)
-			if len(text) > 0 {
-				b.WriteString(text + " ")
+// Writes the visible text under n to w. Text nodes are joined with the
+// separator where the source has whitespace or a non-inline element between
+// them, and with the line break separator at a <br>. Stops at the first
+// write error, returning it. Returns ErrTruncated, after writing what it
+// could, if the document goes past the depth or node limits.
+func writeText(n *html.Node, w io.Writer, o *extractOptions) error {
+	var err error
+	truncated := false
+	nodes := 0
+	first := true
+	sep := ""
+	dedupe := o.newDeduper()
+
+	// A line break outranks a plain separator between the same two words
+	addSep := func(s string) {
+		if sep == "" || sep == o.separator {
+			sep = s
+		}
+	}
+
+	emit := func(text string) {
+		if dedupe.skip(normalizeSpace(text)) {
+			addSep(o.separator)
+			return
+		}
+		if !first {
+			text = sep + text
+		}
+		_, err = io.WriteString(w, text)
+		first = false
+		sep = ""
+	}
+
+	// Walks the tree under root without recursion, so deep documents can't
+	// exhaust the stack, leaving out the elements in skip. Each frame is a
+	// node to visit, whose later siblings are visited after it, or the end
+	// of a non-inline element, where its closing separator goes. The stack
+	// only grows with the depth.
+	type frame struct {
+		n     *html.Node
+		depth int
+		leave bool
+	}
+	walk := func(root *html.Node, depth int, skip map[*html.Node]bool) {
+		stack := []frame{{n: root, depth: depth}}
+
+		for len(stack) > 0 && err == nil {
+			f := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if f.leave {
+				addSep(o.separator)
+				continue
+			}
+
+			n := f.n
+			if n != root && n.NextSibling != nil {
+				stack = append(stack, frame{n: n.NextSibling, depth: f.depth})
+			}
+
+			nodes++
+			if (o.maxDepth > 0 && f.depth > o.maxDepth) || (o.maxNodes > 0 && nodes > o.maxNodes) {
+				truncated = true
+				continue
+			}
+
+			switch {
+			case skip[n] || o.isHidden(n) || o.isIgnorable(n):
+				continue
+			case n.Type == html.TextNode:
+				// Untrimmed text keeps its own whitespace, so only element
+				// boundaries add separators
+				data := o.unescapeText(n.Data)
+				text := data
+				if o.trimEach {
+					text = normalizeSpace(data)
+					if startsWithSpace(data) {
+						addSep(o.separator)
+					}
+				}
+				if len(text) > 0 {
+					emit(text)
+				}
+				if o.trimEach && endsWithSpace(data) {
+					addSep(o.separator)
+				}
+				continue
+			case isElement(n, "br"):
+				addSep(o.lineBreakSeparator())
+				continue
+			case isElement(n, "img") && o.imageAlt:
+				// Stands in for the image like a word in the line
+				if alt := normalizeSpace(o.unescapeText(getAttr(n, "alt"))); alt != "" {
+					emit(alt)
+				}
+				continue
+			case n.Type == html.ElementNode && !(o.joinInline && inlineElements[n.Data]):
+				addSep(o.separator)
+				stack = append(stack, frame{leave: true})
+			}
+
+			if n.FirstChild != nil {
+				stack = append(stack, frame{n: n.FirstChild, depth: f.depth + 1})
 			}
 		}
-		for c := n.FirstChild; c != nil; c = c.NextSibling {
-			traverse(c)
+	}
+
+	// The main content goes first, then the rest of the page around it
+	var done map[*html.Node]bool
+	if o.semanticOrder {
+		roots := semanticRoots(n, o)
+		done = make(map[*html.Node]bool, len(roots))
+		for _, root := range roots {
+			walk(root, depthBelow(root, n), nil)
+			done[root] = true
 		}
 	}
+	walk(n, 0, done)
 
-	traverse(doc)
+	if err == nil && truncated {
+		err = ErrTruncated
+	}
+	return err
+}
 
-	return strings.TrimSpace(b.String()), nil
+func startsWithSpace(s string) bool {
+	r, _ := utf8.DecodeRuneInString(s)
+	return unicode.IsSpace(r)
 }
 
-func isIgnorable(n *html.Node) bool {
-	if n == nil || n.Type != html.ElementNode {
-		return false
+func endsWithSpace(s string) bool {
+	r, _ := utf8.DecodeLastRuneInString(s)
+	return unicode.IsSpace(r)
+}
+
+// Finds the outermost <main> elements under n, or if there are none its
+// outermost <article> elements, in document order, leaving out hidden and
+// ignored ones. Searches without recursion, like writeText.
+func semanticRoots(n *html.Node, o *extractOptions) []*html.Node {
+	for _, tag := range []string{"main", "article"} {
+		var roots []*html.Node
+		stack := []*html.Node{n}
+		for len(stack) > 0 {
+			c := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if c != n && c.NextSibling != nil {
+				stack = append(stack, c.NextSibling)
+			}
+			if o.isHidden(c) || (c.Type == html.ElementNode && o.isIgnorable(c)) {
+				continue
+			}
+			if isElement(c, tag) {
+				roots = append(roots, c)
+				continue
+			}
+			if c.FirstChild != nil {
+				stack = append(stack, c.FirstChild)
+			}
+		}
+		if len(roots) > 0 {
+			return roots
+		}
 	}
+	return nil
+}
 
-	switch n.Data {
-	case "script", "style", "head", "noscript":
-		return true
+// Counts how many levels below top n is
+func depthBelow(n, top *html.Node) int {
+	depth := 0
+	for ; n != top && n != nil; n = n.Parent {
+		depth++
 	}
-	return false
+	return depth
+}
+
+// Collapses every run of whitespace, including non-breaking spaces, into a
+// single space and trims both ends
+func normalizeSpace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
 }
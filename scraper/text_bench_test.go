@@ -0,0 +1,46 @@
+package scraper
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/html"
+)
+
+// A long article: many blocks of mixed inline markup, plus scripts and
+// styles to skip
+func largePage() string {
+	var b strings.Builder
+	b.WriteString("<html><head><style>p{margin:0}</style></head><body>")
+	for i := 0; i < 3000; i++ {
+		b.WriteString(`<div class="post"><h2>Heading</h2><p>Some <b>bold</b> and <a href="/x">linked</a> text, `)
+		b.WriteString(`with <em>emphasis</em> and more words.</p><script>track()</script><ul><li>one</li><li>two</li></ul></div>`)
+	}
+	b.WriteString("</body></html>")
+	return b.String()
+}
+
+// Nesting just inside the default depth limit
+func deepPage() string {
+	const depth = defaultMaxDepth - 10
+	return strings.Repeat("<div>text ", depth) + strings.Repeat("</div>", depth)
+}
+
+func benchmarkWriteText(b *testing.B, page string) {
+	doc, err := html.Parse(strings.NewReader(page))
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for range b.N {
+		if err := writeText(doc, io.Discard, defaultExtractOptions); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkWriteTextLarge(b *testing.B) { benchmarkWriteText(b, largePage()) }
+
+func BenchmarkWriteTextDeep(b *testing.B) { benchmarkWriteText(b, deepPage()) }
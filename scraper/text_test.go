@@ -0,0 +1,174 @@
+package scraper_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/charlescqian/go-scrape/scraper"
+	"github.com/charlescqian/go-scrape/scraper/scrapetest"
+)
+
+func TestExtractText(t *testing.T) {
+	tests := []struct {
+		name string
+		html string
+		want string
+	}{
+		{"plain", "<p>Hello, world</p>", "Hello, world"},
+		{"entities", "<p>Fish &amp; chips &lt;3 &copy; caf&eacute;</p>", "Fish & chips <3 © café"},
+		{"numeric entities", "<p>&#8364;5 &#x2014; cheap</p>", "€5 — cheap"},
+		{"nested tags", "<div><p>One <b>two <i>three</i></b></p><ul><li>four</li><li>five</li></ul></div>", "One two three four five"},
+		{"block boundaries", "<div>left</div><div>right</div>", "left right"},
+		{"scripts and styles", "<head><style>p{color:red}</style></head><body><script>var x = 1;</script><p>visible</p><noscript>enable js</noscript></body>", "visible"},
+		{"whitespace", "<p>  lots \n\n of\tspace  </p>", "lots of space"},
+		{"empty", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := scraper.NewScraper(scraper.WithFetcher(scrapetest.Pages{
+				"https://example.com/": tt.html,
+			}))
+			if err != nil {
+				t.Fatal(err)
+			}
+			scrapetest.AssertText(t, s, "https://example.com/", tt.want)
+
+			// Reading the HTML directly must give the same text
+			got, err := scraper.ExtractTextFromHTML(tt.html)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("ExtractTextFromHTML() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtractTextEncodings(t *testing.T) {
+	tests := []struct {
+		name string
+		page scrapetest.Page
+		want string
+	}{
+		{
+			name: "utf-8",
+			page: scrapetest.Page{Body: "<p>naïve 日本</p>"},
+			want: "naïve 日本",
+		},
+		{
+			name: "latin-1 from header",
+			page: scrapetest.Page{Body: "<p>caf\xe9</p>", ContentType: "text/html; charset=iso-8859-1"},
+			want: "café",
+		},
+		{
+			name: "windows-1252 from meta",
+			page: scrapetest.Page{Body: "<meta charset=\"windows-1252\"><p>\x93quoted\x94</p>", ContentType: "text/html"},
+			want: "“quoted”",
+		},
+		{
+			name: "shift_jis from header",
+			page: scrapetest.Page{Body: "<p>\x93\xfa\x96\x7b</p>", ContentType: "text/html; charset=shift_jis"},
+			want: "日本",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := scrapetest.NewServer(map[string]scrapetest.Page{"/": tt.page})
+			defer srv.Close()
+
+			s, err := scraper.NewScraper()
+			if err != nil {
+				t.Fatal(err)
+			}
+			scrapetest.AssertText(t, s, srv.URL+"/", tt.want)
+		})
+	}
+}
+
+func TestExtractTextStatus(t *testing.T) {
+	srv := scrapetest.NewServer(map[string]scrapetest.Page{
+		"/gone": {Body: "<p>gone</p>", StatusCode: 410},
+	})
+	defer srv.Close()
+
+	s, err := scraper.NewScraper()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for path, want := range map[string]int{"/gone": 410, "/missing": 404} {
+		_, err := s.ExtractText(srv.URL + path)
+		fetchErr, ok := err.(*scraper.FetchError)
+		if !ok || fetchErr.StatusCode != want {
+			t.Errorf("ExtractText(%s) error = %v, want a FetchError with status %d", path, err, want)
+		}
+	}
+}
+
+func TestExtractTextOptions(t *testing.T) {
+	const page = `<p>shown <span hidden>secret</span></p><p><b>pre</b><b>fix</b></p>`
+	tests := []struct {
+		name string
+		opts []scraper.Option
+		want string
+	}{
+		{"defaults", nil, "shown pre fix"},
+		{"keep hidden", []scraper.Option{scraper.WithSkipHidden(false)}, "shown secret pre fix"},
+		{"join inline", []scraper.Option{scraper.WithJoinInline(true)}, "shown prefix"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]scraper.Option{scraper.WithFetcher(scrapetest.Pages{"https://example.com/": page})}, tt.opts...)
+			s, err := scraper.NewScraper(opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			scrapetest.AssertText(t, s, "https://example.com/", tt.want)
+		})
+	}
+}
+
+// Ignored elements are left out along with everything nested in them, by
+// every extractor
+func TestExtractTextIgnoredTags(t *testing.T) {
+	const page = `<html><head><title>T</title></head><body>` +
+		`<nav><ul><li>Home</li><li>About</li></ul></nav>` +
+		`<p>Hello world</p>` +
+		`<footer><div>Copyright</div></footer></body></html>`
+	s, err := scraper.NewScraper(
+		scraper.WithFetcher(scrapetest.Pages{"https://example.com/": page}),
+		scraper.WithIgnoredTags("nav", "footer"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	extractors := []struct {
+		name    string
+		extract func() (string, error)
+	}{
+		{"text", func() (string, error) { return s.ExtractTextContext(ctx, "https://example.com/") }},
+		{"structured", func() (string, error) { return s.ExtractStructuredText(ctx, "https://example.com/") }},
+		{"markdown", func() (string, error) { return s.ExtractMarkdown(ctx, "https://example.com/") }},
+		{"segments", func() (string, error) {
+			segments, err := s.ExtractSegments(ctx, "https://example.com/")
+			var texts []string
+			for _, seg := range segments {
+				texts = append(texts, seg.Text)
+			}
+			return strings.Join(texts, " "), err
+		}},
+	}
+	for _, e := range extractors {
+		t.Run(e.name, func(t *testing.T) {
+			got, err := e.extract()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if strings.TrimSpace(got) != "Hello world" {
+				t.Errorf("got %q, want %q", got, "Hello world")
+			}
+		})
+	}
+}
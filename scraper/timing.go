@@ -0,0 +1,109 @@
+package scraper
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+)
+
+// Where the time went while extracting a page, and how much was downloaded
+type ExtractionStats struct {
+	// From sending the request until the whole body was read, including
+	// any waits for rate limits or robots.txt
+	FetchDuration time.Duration
+	// Parsing the HTML and extracting its text
+	ParseDuration time.Duration
+	// Size of the body as it came over the wire, before its compression
+	// was undone or it was converted to UTF-8
+	BytesDownloaded int64
+}
+
+// Extracts all visible text from a page like ExtractTextWithWarnings, also
+// reporting how long the fetch and the parsing took
+func ExtractWithStats(u string) (*ExtractResult, ExtractionStats, error) {
+	return defaultScraper.ExtractWithStats(context.Background(), u)
+}
+
+// Extracts all visible text from the page at u like ExtractTextWithWarnings,
+// also timing the fetch and the parsing. The body is read in full before it
+// is parsed, so the two don't overlap, and a page reached through meta
+// refreshes counts every page fetched. Always fetches the page, even if the
+// Scraper has a cache.
+func (s *Scraper) ExtractWithStats(ctx context.Context, u string) (*ExtractResult, ExtractionStats, error) {
+	ctx, sink := withWarnings(ctx)
+	ctx, rec := withStats(ctx)
+
+	start := time.Now()
+	text, info, err := s.ExtractWithResponseInfo(ctx, u)
+	if err == nil || isPartial(err) {
+		if shortErr := s.checkContentLength(text); shortErr != nil {
+			err = shortErr
+		}
+	}
+	stats := rec.stats(time.Since(start))
+	if err != nil && !isPartial(err) {
+		return nil, stats, err
+	}
+
+	sink.addPartial(err)
+	return &ExtractResult{URL: info.URL, Text: text, Warnings: sink.warnings}, stats, nil
+}
+
+type statsKey struct{}
+
+// Times the fetches made for one call and counts the bytes they download.
+// Safe for concurrent use.
+type statsRecorder struct {
+	mu    sync.Mutex
+	fetch time.Duration
+	bytes int64
+}
+
+// Returns a copy of ctx whose fetches are recorded in the returned recorder
+func withStats(ctx context.Context) (context.Context, *statsRecorder) {
+	rec := &statsRecorder{}
+	return context.WithValue(ctx, statsKey{}, rec), rec
+}
+
+// Returns the recorder for the call ctx belongs to, nil if it isn't
+// collecting stats
+func statsFrom(ctx context.Context) *statsRecorder {
+	rec, _ := ctx.Value(statsKey{}).(*statsRecorder)
+	return rec
+}
+
+func (r *statsRecorder) addFetch(d time.Duration) {
+	r.mu.Lock()
+	r.fetch += d
+	r.mu.Unlock()
+}
+
+func (r *statsRecorder) addBytes(n int) {
+	r.mu.Lock()
+	r.bytes += int64(n)
+	r.mu.Unlock()
+}
+
+// Splits total, the time the whole call took, into fetching and the rest
+func (r *statsRecorder) stats(total time.Duration) ExtractionStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return ExtractionStats{
+		FetchDuration:   r.fetch,
+		ParseDuration:   max(total-r.fetch, 0),
+		BytesDownloaded: r.bytes,
+	}
+}
+
+// Counts the bytes read through it into rec
+type countingBody struct {
+	io.ReadCloser
+	rec *statsRecorder
+}
+
+func (c *countingBody) Read(p []byte) (int, error) {
+	n, err := c.ReadCloser.Read(p)
+	c.rec.addBytes(n)
+	return n, err
+}
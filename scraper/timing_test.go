@@ -0,0 +1,61 @@
+package scraper_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/charlescqian/go-scrape/scraper"
+)
+
+func TestExtractWithStats(t *testing.T) {
+	// "café" in latin-1, gzipped, so its size on the wire differs from the
+	// decoded text's
+	page := gzipped(t, "<html><body><p>caf\xe9 au lait</p></body></html>")
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=iso-8859-1")
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Write(page)
+	})
+	mux.HandleFunc("/moved", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<meta http-equiv="refresh" content="0; url=/">`))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	s, err := scraper.NewScraper(scraper.WithMetaRefresh(time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, stats, err := s.ExtractWithStats(context.Background(), srv.URL+"/moved")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Text != "café au lait" {
+		t.Errorf("Text = %q, want %q", res.Text, "café au lait")
+	}
+	if res.URL != srv.URL+"/" {
+		t.Errorf("URL = %q, want the page the refresh led to", res.URL)
+	}
+	refresh := int64(len(`<meta http-equiv="refresh" content="0; url=/">`))
+	if want := refresh + int64(len(page)); stats.BytesDownloaded != want {
+		t.Errorf("BytesDownloaded = %d, want %d", stats.BytesDownloaded, want)
+	}
+	if stats.FetchDuration <= 0 {
+		t.Errorf("FetchDuration = %v, want it timed", stats.FetchDuration)
+	}
+
+	// Matches ExtractTextWithWarnings on what counts as too little text
+	s, err = scraper.NewScraper(scraper.WithMinContentLength(100))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := s.ExtractWithStats(context.Background(), srv.URL); !errors.Is(err, scraper.ErrInsufficientContent) {
+		t.Errorf("ExtractWithStats() error = %v, want ErrInsufficientContent", err)
+	}
+}
@@ -0,0 +1,20 @@
+package scraper
+
+import "crypto/x509"
+
+// Disables verification of the server's certificate chain and host name.
+// This makes the connection open to interception, so only use it for
+// internal or staging hosts with self-signed certificates.
+func WithInsecureSkipVerify(skip bool) Option {
+	return func(s *Scraper) {
+		s.insecureSkipVerify = skip
+	}
+}
+
+// Verifies server certificates against pool instead of the system roots,
+// e.g. to trust an internal CA
+func WithRootCAs(pool *x509.CertPool) Option {
+	return func(s *Scraper) {
+		s.rootCAs = pool
+	}
+}
@@ -0,0 +1,100 @@
+package scraper
+
+import (
+	"crypto/tls"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+)
+
+// Idle connections kept per host by default. net/http keeps only 2, which
+// means reconnecting constantly when crawling a single site.
+const defaultMaxIdleConnsPerHost = 16
+
+const (
+	defaultDialTimeout         = 10 * time.Second
+	defaultKeepAlive           = 30 * time.Second
+	defaultTLSHandshakeTimeout = 10 * time.Second
+)
+
+// Sets how many idle connections are kept open for reuse with each host
+func WithMaxIdleConnsPerHost(n int) Option {
+	return func(s *Scraper) {
+		s.maxIdleConnsPerHost = n
+	}
+}
+
+// Sends requests with c as it is, instead of a client built from the
+// Scraper's options. c takes precedence over the options that configure the
+// client itself: WithTimeout, WithProxy, WithCookieJar, the TLS options and
+// the connection and timeout options in this file have no effect, and HTTP
+// redirects follow c's CheckRedirect policy. Everything else, such as
+// headers, retries, rate limits and robots.txt, still applies.
+func WithHTTPClient(c *http.Client) Option {
+	return func(s *Scraper) {
+		if c == nil {
+			s.optErrs = append(s.optErrs, errors.New("HTTP client must not be nil"))
+			return
+		}
+		s.customClient = c
+	}
+}
+
+// Limits how long resolving a host and opening a connection to it may take,
+// so a slow DNS server fails the request early instead of using up all of
+// its timeout. Zero or less means no limit beyond the overall timeout.
+func WithDialTimeout(d time.Duration) Option {
+	return func(s *Scraper) {
+		s.dialTimeout = d
+	}
+}
+
+// Limits how long the TLS handshake with a server may take. Zero or less
+// means no limit beyond the overall timeout.
+func WithTLSHandshakeTimeout(d time.Duration) Option {
+	return func(s *Scraper) {
+		s.tlsHandshakeTimeout = d
+	}
+}
+
+// Limits how long to wait for a server's response headers once the request
+// has been sent. No limit beyond the overall timeout by default.
+func WithResponseHeaderTimeout(d time.Duration) Option {
+	return func(s *Scraper) {
+		s.responseHeaderTimeout = d
+	}
+}
+
+// Builds the transport shared by all of the Scraper's requests, so
+// connections are pooled across calls and HTTP/2 is used where the server
+// supports it
+func (s *Scraper) newTransport() *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.ForceAttemptHTTP2 = true
+	dialer := &net.Dialer{
+		Timeout:   max(s.dialTimeout, 0),
+		KeepAlive: defaultKeepAlive,
+	}
+	transport.DialContext = dialer.DialContext
+	transport.TLSHandshakeTimeout = max(s.tlsHandshakeTimeout, 0)
+	transport.ResponseHeaderTimeout = max(s.responseHeaderTimeout, 0)
+	transport.MaxIdleConnsPerHost = s.maxIdleConnsPerHost
+	if transport.MaxIdleConns > 0 && transport.MaxIdleConns < s.maxIdleConnsPerHost {
+		transport.MaxIdleConns = s.maxIdleConnsPerHost
+	}
+
+	if s.proxy != nil {
+		transport.Proxy = http.ProxyURL(s.proxy)
+	}
+	if s.insecureSkipVerify || s.rootCAs != nil {
+		transport.TLSClientConfig = &tls.Config{
+			InsecureSkipVerify: s.insecureSkipVerify,
+			RootCAs:            s.rootCAs,
+		}
+	}
+	if s.localFiles {
+		transport.RegisterProtocol("file", http.NewFileTransport(http.Dir("/")))
+	}
+	return transport
+}
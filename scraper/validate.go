@@ -0,0 +1,34 @@
+package scraper
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Returned by ValidateURL for URLs the Scraper can't fetch
+var ErrInvalidURL = errors.New("invalid URL")
+
+// Checks that u is an absolute http or https URL with a host, which is what
+// the fetching functions expect. The returned error wraps ErrInvalidURL and
+// says what's wrong.
+func ValidateURL(u string) error {
+	if strings.TrimSpace(u) == "" {
+		return fmt.Errorf("%w: empty", ErrInvalidURL)
+	}
+
+	parsed, err := url.ParseRequestURI(u)
+	if err != nil || parsed.Scheme == "" {
+		return fmt.Errorf("%w: %q is not an absolute URL", ErrInvalidURL, u)
+	}
+	switch strings.ToLower(parsed.Scheme) {
+	case "http", "https":
+	default:
+		return fmt.Errorf("%w: unsupported scheme %q, expected http or https", ErrInvalidURL, parsed.Scheme)
+	}
+	if parsed.Host == "" {
+		return fmt.Errorf("%w: %q has no host", ErrInvalidURL, u)
+	}
+	return nil
+}
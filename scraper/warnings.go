@@ -0,0 +1,96 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// Something off about a page that didn't stop its content being extracted
+type Warning struct {
+	// One of the Warning* codes
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// Codes of the warnings extraction can give
+const (
+	// The page went past the extraction limits, see ErrTruncated
+	WarningTruncated = "truncated"
+	// The download broke off, see ErrIncomplete
+	WarningIncomplete = "incomplete"
+	// The page declared a charset that isn't known, so it was read as UTF-8
+	WarningUnknownCharset = "unknown_charset"
+	// The page declared no charset and isn't valid UTF-8, so its encoding
+	// was guessed
+	WarningGuessedCharset = "guessed_charset"
+)
+
+// The text of a page along with any warnings about it
+type ExtractResult struct {
+	// The URL the page was finally served from, after redirects
+	URL      string
+	Text     string
+	Warnings []Warning
+}
+
+// Extracts all visible text from a page like ExtractTextWithURL, reporting
+// what was off about it as warnings instead of errors
+func ExtractTextWithWarnings(u string) (*ExtractResult, error) {
+	return defaultScraper.ExtractTextWithWarnings(context.Background(), u)
+}
+
+// Extracts all visible text from the page at u like ExtractTextWithURL, but
+// a page past the extraction limits or whose download broke off gives a
+// warning rather than an error, as does a page whose encoding is in doubt.
+// Text served from the Scraper's cache comes without warnings.
+func (s *Scraper) ExtractTextWithWarnings(ctx context.Context, u string) (*ExtractResult, error) {
+	ctx, sink := withWarnings(ctx)
+	text, finalURL, err := s.ExtractTextWithURL(ctx, u)
+	if err != nil && !isPartial(err) {
+		return nil, err
+	}
+
+	sink.addPartial(err)
+	return &ExtractResult{URL: finalURL, Text: text, Warnings: sink.warnings}, nil
+}
+
+type warningsKey struct{}
+
+// Collects the warnings raised while handling one call. Safe for
+// concurrent use.
+type warningSink struct {
+	mu       sync.Mutex
+	warnings []Warning
+}
+
+func (ws *warningSink) add(w Warning) {
+	ws.mu.Lock()
+	ws.warnings = append(ws.warnings, w)
+	ws.mu.Unlock()
+}
+
+// Records the warnings for a partial extraction's ErrTruncated or
+// ErrIncomplete, if err is one
+func (ws *warningSink) addPartial(err error) {
+	if errors.Is(err, ErrTruncated) {
+		ws.add(Warning{Code: WarningTruncated, Message: ErrTruncated.Error()})
+	}
+	if errors.Is(err, ErrIncomplete) {
+		ws.add(Warning{Code: WarningIncomplete, Message: ErrIncomplete.Error()})
+	}
+}
+
+// Returns a copy of ctx whose requests record their warnings in the
+// returned sink
+func withWarnings(ctx context.Context) (context.Context, *warningSink) {
+	sink := &warningSink{}
+	return context.WithValue(ctx, warningsKey{}, sink), sink
+}
+
+// Records w against the call ctx belongs to, if it's collecting warnings
+func addWarning(ctx context.Context, w *Warning) {
+	if sink, ok := ctx.Value(warningsKey{}).(*warningSink); ok && w != nil {
+		sink.add(*w)
+	}
+}